@@ -9,6 +9,12 @@ import (
 
 type Logger = logger.Logger
 type Option = logger.Option
+type Destination = logger.Destination
+type CallSite = logger.CallSite
+type CallSiteFilter = logger.CallSiteFilter
+type PanicError = logger.PanicError
+type GCPOptions = logger.GCPOptions
+type DatadogOptions = logger.DatadogOptions
 
 func WithLevel(level slog.Level) Option {
 	return logger.WithLevel(level)
@@ -30,6 +36,56 @@ func WithTextHandler(w io.Writer) Option {
 	return logger.WithTextHandler(w)
 }
 
+// WithGCPHandler writes JSON in the Google Cloud Logging structured layout to w.
+func WithGCPHandler(w io.Writer, opts GCPOptions) Option {
+	return logger.WithGCPHandler(w, opts)
+}
+
+// WithECSHandler writes JSON with Elastic Common Schema field names to w.
+func WithECSHandler(w io.Writer) Option {
+	return logger.WithECSHandler(w)
+}
+
+// WithDatadogHandler writes JSON with Datadog reserved attributes to w.
+func WithDatadogHandler(w io.Writer, opts DatadogOptions) Option {
+	return logger.WithDatadogHandler(w, opts)
+}
+
+// WithLogfmtHandler writes logfmt logs to w.
+func WithLogfmtHandler(w io.Writer) Option {
+	return logger.WithLogfmtHandler(w)
+}
+
+// WithConsoleHandler writes colorized, human-readable logs to w.
+func WithConsoleHandler(w io.Writer) Option {
+	return logger.WithConsoleHandler(w)
+}
+
+// WithHandler configures h as the logger backend, e.g. an otel.OtelHandler.
+func WithHandler(h slog.Handler) Option {
+	return logger.WithHandler(h)
+}
+
+// WithMultiHandler sends every record to all destinations whose level accepts it.
+func WithMultiHandler(destinations ...Destination) Option {
+	return logger.WithMultiHandler(destinations...)
+}
+
+// WithExitFunc replaces os.Exit as the function called by Fatal.
+func WithExitFunc(exit func(code int)) Option {
+	return logger.WithExitFunc(exit)
+}
+
+// WithExitCode sets the code Fatal exits with. Defaults to 1.
+func WithExitCode(code int) Option {
+	return logger.WithExitCode(code)
+}
+
+// WithReopenFile writes JSON records to f and reopens it on SIGHUP.
+func WithReopenFile(f *logger.ReopenFile) Option {
+	return logger.WithReopenFile(f)
+}
+
 // New creates a configurable logger instance without touching package-level state.
 func New(opts ...Option) *Logger {
 	return logger.NewWithOptions(opts...)
@@ -44,3 +100,21 @@ func SetSource(enabled bool) {
 func SetDebugStackTrace(enabled bool) {
 	std.SetDebugStackTrace(enabled)
 }
+
+// SetExitFunc replaces os.Exit as the function called by the package-level
+// Fatal and FatalC. A nil exit restores os.Exit.
+func SetExitFunc(exit func(code int)) {
+	std.SetExitFunc(exit)
+}
+
+// SetExitCode sets the code the package-level Fatal and FatalC exit with.
+func SetExitCode(code int) {
+	std.SetExitCode(code)
+}
+
+// With returns a child of the package-level logger that includes the given
+// attributes in each record. Setters called on the child change the
+// package-level logger.
+func With(args ...any) *Logger {
+	return std.With(args...)
+}
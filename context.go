@@ -0,0 +1,33 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jgolang/log/logger"
+)
+
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx that carries l.
+// The package-level *C functions log through the logger found in the context.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx,
+// or the package-level logger when ctx has none.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return std
+}
+
+// AddAttrs returns a copy of ctx carrying attrs in addition to those already
+// added. Every record logged with the returned context includes them.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return logger.AddAttrs(ctx, attrs...)
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestContextFunctionsUseLoggerFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	requestLogger := New(WithJSONHandler(&buf)).With("request_id", "abc")
+	ctx := NewContext(context.Background(), requestLogger)
+
+	InfoC(ctx, "hello")
+
+	if FromContext(ctx) != requestLogger {
+		t.Fatalf("FromContext() did not return the stored logger")
+	}
+	if !strings.Contains(buf.String(), "\"request_id\":\"abc\"") {
+		t.Fatalf("expected request logger output, got %q", buf.String())
+	}
+}
+
+func TestFromContextFallsBackToPackageLogger(t *testing.T) {
+	if got := FromContext(context.Background()); got != std {
+		t.Fatalf("FromContext() = %p, want package logger %p", got, std)
+	}
+}
+
+func TestAddAttrsAreAppendedToContextRecords(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(WithJSONHandler(&buf), WithSource(false))
+	ctx := AddAttrs(context.Background(), slog.String("tenant", "acme"))
+	ctx = AddAttrs(ctx, slog.String("user", "42"))
+
+	instance.InfoContext(ctx, "hello", "route", "/items")
+	instance.Info("plain")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "\"route\":\"/items\",\"tenant\":\"acme\",\"user\":\"42\"") {
+		t.Fatalf("expected stacked context attributes, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], "tenant") {
+		t.Fatalf("expected no context attributes without context, got %q", lines[1])
+	}
+}
@@ -10,13 +10,13 @@ func Debug(args ...any) {
 	std.Debug(msg, attrs...)
 }
 
-// DebugC logs a debug-level message with context using the global logger.
+// DebugC logs a debug-level message with context using the logger carried by ctx or the global logger.
 // ctx: The context for the log entry.
 // msg: The message to log.
 // args: Additional arguments to format the message.
 func DebugC(ctx context.Context, args ...any) {
 	msg, attrs := validateArgs(args...)
-	std.DebugContext(ctx, msg, attrs...)
+	FromContext(ctx).DebugContext(ctx, msg, attrs...)
 }
 
 // Warn logs a warning-level message using the global logger.
@@ -27,11 +27,11 @@ func Warn(args ...any) {
 	std.Warn(msg, attrs...)
 }
 
-// WarnC logs a warning-level message with context using the global logger.
+// WarnC logs a warning-level message with context using the logger carried by ctx or the global logger.
 // ctx: The context for the log entry.
 // msg: The message to log.
 // args: Additional arguments to format the message.
 func WarnC(ctx context.Context, args ...interface{}) {
 	msg, attrs := validateArgs(args...)
-	std.WarnContext(ctx, msg, attrs...)
+	FromContext(ctx).WarnContext(ctx, msg, attrs...)
 }
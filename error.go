@@ -10,11 +10,11 @@ func Error(args ...any) {
 	std.Error(msg, attrs...)
 }
 
-// ErrorC logs an error-level message with context using the global logger.
+// ErrorC logs an error-level message with context using the logger carried by ctx or the global logger.
 // ctx: The context for the log entry..
 func ErrorC(ctx context.Context, args ...interface{}) {
 	msg, attrs := validateArgs(args...)
-	std.ErrorContext(ctx, msg, attrs...)
+	FromContext(ctx).ErrorContext(ctx, msg, attrs...)
 }
 
 // Panic logs a panic-level message using the global logger and then panics.
@@ -23,22 +23,22 @@ func Panic(args ...any) {
 	std.Panic(msg, attrs...)
 }
 
-// PanicC logs a panic-level message with context using the global logger and then panics.
+// PanicC logs a panic-level message with context using the logger carried by ctx or the global logger and then panics.
 // ctx: The context for the log entry.
 func PanicC(ctx context.Context, args ...any) {
 	msg, attrs := validateArgs(args...)
-	std.PanicContext(ctx, msg, attrs...)
+	FromContext(ctx).PanicContext(ctx, msg, attrs...)
 }
 
-// Fatal logs a fatal-level message using the global logger and then calls os.Exit(1).
+// Fatal logs a fatal-level message using the global logger, flushes it and then calls os.Exit(1).
 func Fatal(args ...any) {
 	msg, attrs := validateArgs(args...)
 	std.Fatal(msg, attrs...)
 }
 
-// FatalC logs a fatal-level message with context using the global logger and then calls os.Exit(1).
+// FatalC logs a fatal-level message with context using the logger carried by ctx or the global logger, flushes it and then calls os.Exit(1).
 // ctx: The context for the log entry.
 func FatalC(ctx context.Context, args ...any) {
 	msg, attrs := validateArgs(args...)
-	std.FatalContext(ctx, msg, attrs...)
+	FromContext(ctx).FatalContext(ctx, msg, attrs...)
 }
@@ -0,0 +1,22 @@
+package log
+
+import (
+	"context"
+
+	"github.com/jgolang/log/logger"
+)
+
+type Event = logger.Event
+
+// StartEvent starts a wide event named name using the logger carried by ctx
+// or the global logger. Fields set on the event are logged as a single record
+// when End is called.
+func StartEvent(ctx context.Context, name string) *Event {
+	return FromContext(ctx).StartEvent(ctx, name)
+}
+
+// EventFromContext returns the event attached to ctx, or nil.
+// Methods on a nil event are no-ops.
+func EventFromContext(ctx context.Context) *Event {
+	return logger.EventFromContext(ctx)
+}
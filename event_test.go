@@ -0,0 +1,77 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+func TestEventEmitsSingleRecordWithAccumulatedFields(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(WithJSONHandler(&buf), WithLevel(slog.LevelInfo))
+	ev := instance.StartEvent(context.Background(), "http.request")
+	ctx := ev.Context()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			EventFromContext(ctx).Add("db.queries", 1)
+		}()
+	}
+	wg.Wait()
+	EventFromContext(ctx).Set("route", "/items")
+	ev.End()
+	ev.End()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; output = %q", err, buf.String())
+	}
+	if entry["msg"] != "http.request" || entry["level"] != "INFO" {
+		t.Fatalf("unexpected msg or level: %v", entry)
+	}
+	if entry["db.queries"] != float64(10) {
+		t.Fatalf("db.queries = %v, want 10", entry["db.queries"])
+	}
+	if entry["route"] != "/items" {
+		t.Fatalf("route = %v, want /items", entry["route"])
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Fatalf("expected duration in output, got %v", entry)
+	}
+	if _, ok := entry["source"]; !ok {
+		t.Fatalf("expected source in output, got %v", entry)
+	}
+}
+
+func TestEventWithErrorEndsAtErrorLevel(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(WithJSONHandler(&buf), WithLevel(slog.LevelError))
+	ev := instance.StartEvent(context.Background(), "job")
+	ev.SetError(errors.New("failed"))
+	ev.End()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; output = %q", err, buf.String())
+	}
+	if entry["level"] != "ERROR" {
+		t.Fatalf("level = %v, want ERROR", entry["level"])
+	}
+	if entry["error"] != "failed" {
+		t.Fatalf("error = %v, want failed", entry["error"])
+	}
+}
+
+func TestEventFromContextWithoutEventIsNoop(t *testing.T) {
+	ev := EventFromContext(context.Background())
+	ev.Set("key", "value")
+	ev.Add("count", 1)
+	ev.End()
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -24,6 +25,17 @@ func NewTextHandler() {
 	std.SetTextHandler(os.Stderr)
 }
 
+// NewLogfmtHandler switches package-level logs to logfmt on stderr.
+func NewLogfmtHandler() {
+	std.SetLogfmtHandler(os.Stderr)
+}
+
+// NewConsoleHandler switches package-level logs to the colorized console
+// format on stderr.
+func NewConsoleHandler() {
+	std.SetConsoleHandler(os.Stderr)
+}
+
 // SetCalldepth configure the number of stack frames
 // to ascend, with 0 identifying the caller of Caller for default loggin
 func SetCalldepth(calldepth int) {
@@ -59,6 +71,57 @@ func Level() slog.Level {
 	return level.Level()
 }
 
+// Named returns a child of the global logger named name. Its level is resolved
+// hierarchically from the levels set with SetNamedLevel: "db.pool" uses the
+// level of "db.pool", then "db", then the global level.
+func Named(name string) *Logger {
+	return std.Named(name)
+}
+
+// SetNamedLevel sets the level of the named logger and its descendants at runtime.
+func SetNamedLevel(name string, level slog.Level) {
+	std.SetNamedLevel(name, level)
+}
+
+// ClearNamedLevel removes the level set for name, so it falls back to its
+// closest configured ancestor or the global level.
+func ClearNamedLevel(name string) {
+	std.ClearNamedLevel(name)
+}
+
+// CallSites returns the DEBUG and TRACE call sites of the global logger.
+func CallSites() []CallSite {
+	return std.CallSites()
+}
+
+// EnableCallSites makes the call sites matched by filter log at level and
+// above without lowering the global level.
+func EnableCallSites(filter CallSiteFilter, level slog.Level) {
+	std.EnableCallSites(filter, level)
+}
+
+// DisableCallSites stops the call sites matched by filter from logging DEBUG
+// and TRACE records.
+func DisableCallSites(filter CallSiteFilter) {
+	std.DisableCallSites(filter)
+}
+
+// ResetCallSites removes the call site rules of the global logger.
+func ResetCallSites() {
+	std.ResetCallSites()
+}
+
+// OnExit registers a hook run by Fatal before the global logger is flushed
+// and the application exits. Hooks run in registration order.
+func OnExit(hook func()) {
+	std.OnExit(hook)
+}
+
+// Sync flushes any buffered records of the global logger.
+func Sync() error {
+	return std.Flush(context.Background())
+}
+
 func validateArgs(args ...any) (string, []any) {
 	if len(args) == 0 {
 		return "", nil
@@ -78,13 +141,13 @@ func validateArgs(args ...any) (string, []any) {
 			if err.Wrapper != nil {
 				debug = err.Wrapper.Error()
 			}
-			errGroup := slog.Group("error",
-				"code", err.Code.Str(),
-				"msg", err.Code.Msg(),
-				"debug", debug,
-				"origin", err.StackTrace(),
+			errGroup := slog.GroupValue(
+				slog.String("code", err.Code.Str()),
+				slog.String("msg", err.Code.Msg()),
+				slog.String("debug", debug),
+				slog.Any("origin", err.StackTrace()),
 			)
-			rest = append(rest, errGroup)
+			rest = append(rest, slog.Any("error", logger.ErrorGroup{Err: err, Value: errGroup}))
 		} else {
 			rest = append(rest, "error", v)
 		}
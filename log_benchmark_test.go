@@ -39,6 +39,14 @@ func BenchmarkLoggerInfo(b *testing.B) {
 				logger.Debug("hello", "request_id", "abc")
 			},
 		},
+		{
+			name:      "disabled_debug",
+			addSource: true,
+			level:     slog.LevelInfo,
+			log: func(logger *Logger) {
+				logger.Debug("hello", "request_id", "abc")
+			},
+		},
 		{
 			name:      "disabled_level",
 			addSource: true,
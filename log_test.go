@@ -4,10 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/jgolang/errors"
+	"github.com/jgolang/errors/codes"
+	"github.com/jgolang/log/logger"
 )
 
 func TestSetLevelUpdatesCurrentLevel(t *testing.T) {
@@ -23,15 +29,86 @@ func TestSetLevelUpdatesCurrentLevel(t *testing.T) {
 
 func TestPanicContextPanicsWithMessage(t *testing.T) {
 	defer func() {
-		recovered := recover()
-		if recovered != "boom" {
-			t.Fatalf("panic = %v, want %q", recovered, "boom")
+		recovered, ok := recover().(*PanicError)
+		if !ok || recovered.Message != "boom" {
+			t.Fatalf("panic = %v, want *PanicError with message %q", recovered, "boom")
 		}
 	}()
 
 	std.PanicContext(context.Background(), "boom")
 }
 
+func TestPanicCarriesAttrsAndOriginalError(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(WithJSONHandler(&buf))
+	ctx := NewContext(context.Background(), instance)
+	cause := errors.NewC(codes.DBQuery, "query failed")
+
+	defer func() {
+		recovered, ok := recover().(*PanicError)
+		if !ok {
+			t.Fatalf("expected *PanicError panic value")
+		}
+		if recovered.Err != cause {
+			t.Fatalf("PanicError.Err = %v, want %v", recovered.Err, cause)
+		}
+		if len(recovered.Attrs) != 2 || recovered.Attrs[0].Key != "table" {
+			t.Fatalf("PanicError.Attrs = %v, want table and error", recovered.Attrs)
+		}
+		if !strings.Contains(buf.String(), "\"error\":{\"code\":\"d002\"") {
+			t.Fatalf("expected error group in output, got %q", buf.String())
+		}
+	}()
+
+	PanicC(ctx, cause, "table", "users")
+}
+
+func TestFatalRunsExitHooksAndExitFunc(t *testing.T) {
+	var buf bytes.Buffer
+	var calls []string
+	instance := New(
+		WithJSONHandler(&buf),
+		WithExitCode(3),
+		WithExitFunc(func(code int) {
+			calls = append(calls, fmt.Sprintf("exit %d", code))
+		}),
+	)
+	instance.OnExit(func() { calls = append(calls, "first") })
+	instance.With("component", "db").OnExit(func() { calls = append(calls, "second") })
+
+	instance.Fatal("stopping")
+
+	if got := strings.Join(calls, ","); got != "first,second,exit 3" {
+		t.Fatalf("calls = %q, want first,second,exit 3", got)
+	}
+	if !strings.Contains(buf.String(), "\"level\":\"FATAL\"") {
+		t.Fatalf("expected fatal record, got %q", buf.String())
+	}
+}
+
+func TestPackageFatalUsesExitFuncAndCode(t *testing.T) {
+	var buf bytes.Buffer
+	std.SetJSONHandler(&buf)
+	var codes []int
+	SetExitFunc(func(code int) { codes = append(codes, code) })
+	SetExitCode(3)
+	t.Cleanup(func() {
+		NewJSONHandler()
+		SetExitFunc(nil)
+		SetExitCode(1)
+	})
+
+	Fatal("shutting down")
+	FatalC(context.Background(), "shutting down with context")
+
+	if len(codes) != 2 || codes[0] != 3 || codes[1] != 3 {
+		t.Fatalf("exit codes = %v, want [3 3]", codes)
+	}
+	if got := strings.Count(buf.String(), "\"level\":\"FATAL\""); got != 2 {
+		t.Fatalf("fatal records = %d, want 2; output = %q", got, buf.String())
+	}
+}
+
 func TestInfoUsesConfiguredHandler(t *testing.T) {
 	var buf bytes.Buffer
 	std.SetJSONHandler(&buf)
@@ -147,3 +224,188 @@ func TestLoggerSupportsConcurrentConfigurationAndLogging(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestWithAddsAttributesToChildOnly(t *testing.T) {
+	var buf bytes.Buffer
+	parent := New(WithJSONHandler(&buf), WithLevel(slog.LevelInfo))
+	child := parent.With("component", "db").WithGroup("query")
+
+	child.Info("child", "table", "users")
+	parent.Info("parent")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "\"component\":\"db\"") || !strings.Contains(lines[0], "\"query\":{\"table\":\"users\"") {
+		t.Fatalf("expected child attributes and group, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], "component") {
+		t.Fatalf("expected parent without child attributes, got %q", lines[1])
+	}
+}
+
+func TestWithFollowsParentHandlerAndSettings(t *testing.T) {
+	var first, second bytes.Buffer
+	parent := New(WithJSONHandler(&first), WithLevel(slog.LevelInfo))
+	child := parent.With("component", "db")
+
+	parent.SetJSONHandler(&second)
+	parent.SetSource(false)
+	parent.SetLevel(slog.LevelWarn)
+
+	child.Info("hidden")
+	child.Warn("visible")
+
+	if first.Len() != 0 {
+		t.Fatalf("expected old handler to stay empty, got %q", first.String())
+	}
+	output := second.String()
+	if strings.Contains(output, "hidden") {
+		t.Fatalf("expected child to share parent level, got %q", output)
+	}
+	if !strings.Contains(output, "\"component\":\"db\"") {
+		t.Fatalf("expected child attributes on swapped handler, got %q", output)
+	}
+	if strings.Contains(output, "\"source\"") {
+		t.Fatalf("expected child to share parent source setting, got %q", output)
+	}
+}
+
+func TestSettersOnChildChangeTheParent(t *testing.T) {
+	var first, second bytes.Buffer
+	parent := New(WithJSONHandler(&first), WithLevel(slog.LevelInfo))
+	child := parent.WithGroup("db")
+
+	child.SetJSONHandler(&second)
+	child.SetLevel(slog.LevelWarn)
+
+	parent.Info("hidden")
+	parent.Warn("visible")
+
+	if first.Len() != 0 || strings.Contains(second.String(), "hidden") || !strings.Contains(second.String(), "visible") {
+		t.Fatalf("expected parent to use the child's handler and level, got %q and %q", first.String(), second.String())
+	}
+}
+
+func TestWithHandlerEnforcesLevelAndSource(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(
+		WithHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		WithLevel(slog.LevelWarn),
+	)
+
+	instance.Info("hidden")
+	instance.Error("visible")
+
+	output := buf.String()
+	if strings.Contains(output, "hidden") {
+		t.Fatalf("expected logger level to filter custom handler, got %q", output)
+	}
+	if !strings.Contains(output, "\"msg\":\"visible\"") || !strings.Contains(output, "\"source\"") {
+		t.Fatalf("expected message with source metadata, got %q", output)
+	}
+}
+
+func TestSetHandlerWithHandlerOptionsRendersCustomLevels(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(WithLevel(logger.LevelTrace))
+	instance.SetHandler(slog.NewTextHandler(&buf, instance.HandlerOptions()))
+
+	instance.Print("hello")
+
+	if !strings.Contains(buf.String(), "level=TRACE") {
+		t.Fatalf("expected TRACE level name, got %q", buf.String())
+	}
+}
+
+func TestSetHandlerRendersCustomLevelsOnPlainHandlers(t *testing.T) {
+	var buf bytes.Buffer
+	plain := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: logger.LevelTrace,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	instance := New(WithLevel(logger.LevelTrace), WithSource(false), WithHandler(plain))
+
+	instance.Print("trace")
+	instance.With("level", "custom").WithGroup("req").Info("info", "level", logger.LevelFatal)
+	slog.New(plain).Log(context.Background(), logger.LevelTrace, "direct")
+
+	want := []string{
+		`{"level":"TRACE","msg":"trace"}`,
+		`{"level":"INFO","msg":"info","level":"custom","req":{"level":"ERROR+4"}}`,
+		`{"level":"DEBUG-4","msg":"direct"}`,
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !slices.Equal(got, want) {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+}
+
+func TestDedupHandlerFingerprintsErrorCodes(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New()
+	dedup := logger.NewDedupHandler(
+		slog.NewJSONHandler(&buf, instance.HandlerOptions()),
+		logger.DedupOptions{Keys: []string{"error.code"}},
+	)
+	instance.SetHandler(dedup)
+	ctx := NewContext(context.Background(), instance)
+
+	for i := 0; i < 3; i++ {
+		ErrorC(ctx, errors.NewC(codes.NetTimeout, "dependency down"))
+	}
+	ErrorC(ctx, errors.NewC(codes.ServiceDown, "dependency down"))
+	if err := dedup.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected two records and one summary, got %q", buf.String())
+	}
+	if !strings.Contains(lines[2], "\"code\":\"n001\"") || !strings.Contains(lines[2], "\"repeated\":2") {
+		t.Fatalf("expected summary for the repeated code, got %q", lines[2])
+	}
+}
+
+func TestECSHandlerMapsErrorGroup(t *testing.T) {
+	var buf bytes.Buffer
+	instance := New(WithECSHandler(&buf))
+	// ErrorC adds a frame above the instance methods.
+	instance.SetCalldepth(7)
+	ctx := NewContext(context.Background(), instance)
+
+	ErrorC(ctx, errors.NewC(codes.DBQuery, "query failed"))
+	var record struct {
+		Error struct {
+			Code       string `json:"code"`
+			Message    string `json:"message"`
+			StackTrace string `json:"stack_trace"`
+		} `json:"error"`
+		Log struct {
+			Origin struct {
+				File struct {
+					Name string `json:"name"`
+				} `json:"file"`
+			} `json:"origin"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Unmarshal(%q) error = %v", buf.String(), err)
+	}
+	if record.Error.Code != codes.DBQuery.Str() || !strings.Contains(record.Error.Message, "query failed") {
+		t.Fatalf("error = %+v, want code %s and the error message", record.Error, codes.DBQuery.Str())
+	}
+	if !strings.Contains(record.Error.StackTrace, "log_test.go:") {
+		t.Fatalf("error.stack_trace = %q, want the error origin frames", record.Error.StackTrace)
+	}
+	if record.Log.Origin.File.Name != "log_test.go" {
+		t.Fatalf("log.origin.file.name = %q, want log_test.go", record.Log.Origin.File.Name)
+	}
+}
@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what an AsyncHandler does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the incoming record.
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest queued record to make room. It
+	// blocks when the queue only holds pending flushes.
+	OverflowDropOldest
+	// OverflowDropBelowLevel drops incoming records below
+	// AsyncOptions.DropBelow and blocks for the others.
+	OverflowDropBelowLevel
+)
+
+// ErrHandlerClosed is returned by handlers that no longer accept records.
+var ErrHandlerClosed = errors.New("logger: handler closed")
+
+// AsyncOptions configures an AsyncHandler.
+type AsyncOptions struct {
+	// QueueSize bounds the number of queued records. Defaults to 1024.
+	QueueSize int
+	// Overflow is the policy applied when the queue is full.
+	Overflow OverflowPolicy
+	// DropBelow is the level under which OverflowDropBelowLevel drops records.
+	DropBelow slog.Level
+	// OnError receives the errors returned by the next handler.
+	OnError func(error)
+}
+
+type asyncItem struct {
+	ctx    context.Context
+	next   slog.Handler
+	record slog.Record
+	flush  chan struct{} // Set for flush markers instead of a record.
+}
+
+// asyncState is shared by an AsyncHandler and the handlers derived from it.
+type asyncState struct {
+	opts    AsyncOptions
+	queue   chan asyncItem
+	mu      sync.RWMutex // Guards closed against concurrent sends.
+	closed  bool
+	closing chan struct{} // Closed first by Close to release blocked senders.
+	once    sync.Once
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+// AsyncHandler queues records and hands them to the next handler from a
+// background goroutine, so slow writers do not stall the logging caller.
+// Call Close to drain the queue and stop the goroutine.
+type AsyncHandler struct {
+	next  slog.Handler
+	state *asyncState
+}
+
+// NewAsyncHandler creates an AsyncHandler that writes to next in the background.
+func NewAsyncHandler(next slog.Handler, opts AsyncOptions) *AsyncHandler {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	state := &asyncState{
+		opts:    opts,
+		queue:   make(chan asyncItem, opts.QueueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go state.run()
+	return &AsyncHandler{next: next, state: state}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle queues the record according to the overflow policy.
+func (h *AsyncHandler) Handle(ctx context.Context, record slog.Record) error {
+	item := asyncItem{
+		ctx:    context.WithoutCancel(ctx),
+		next:   h.next,
+		record: record.Clone(),
+	}
+	return h.state.enqueue(item)
+}
+
+// WithAttrs returns an AsyncHandler sharing the queue of h.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup returns an AsyncHandler sharing the queue of h.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Dropped returns the number of records dropped by the overflow policy.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.state.dropped.Load()
+}
+
+// Flush waits until the records queued before the call are handled, or ctx
+// is done, then flushes the next handler.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	if err := h.state.send(ctx, asyncItem{flush: done}); err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return FlushHandler(ctx, h.next)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting records, waits until the queue is drained, or ctx
+// is done, then closes the next handler. Records handled after Close, or
+// still waiting for room in the queue, return ErrHandlerClosed.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	s := h.state
+	// Release the senders blocked on a full queue, so they drop their read
+	// lock even when the next handler is stuck.
+	s.once.Do(func() { close(s.closing) })
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	select {
+	case <-s.done:
+		return CloseHandler(ctx, h.next)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *asyncState) run() {
+	defer close(s.done)
+	for item := range s.queue {
+		if item.flush != nil {
+			close(item.flush)
+			continue
+		}
+		if err := item.next.Handle(item.ctx, item.record); err != nil && s.opts.OnError != nil {
+			s.opts.OnError(err)
+		}
+	}
+}
+
+func (s *asyncState) enqueue(item asyncItem) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrHandlerClosed
+	}
+	select {
+	case s.queue <- item:
+		return nil
+	default:
+	}
+
+	switch s.opts.Overflow {
+	case OverflowDropNewest:
+		s.dropped.Add(1)
+		return nil
+	case OverflowDropOldest:
+		// A queue holding only flush markers has nothing to drop: after a
+		// pass over it, the record waits for room as with OverflowBlock.
+		for markers := 0; markers < cap(s.queue); {
+			select {
+			case s.queue <- item:
+				return nil
+			default:
+			}
+			select {
+			case old := <-s.queue:
+				if old.flush != nil {
+					// Flush markers are never dropped; requeue it at the back,
+					// still ahead of the record.
+					select {
+					case s.queue <- old:
+					case <-s.closing:
+						close(old.flush)
+						return ErrHandlerClosed
+					}
+					markers++
+					continue
+				}
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	case OverflowDropBelowLevel:
+		if item.record.Level < s.opts.DropBelow {
+			s.dropped.Add(1)
+			return nil
+		}
+	}
+	select {
+	case s.queue <- item:
+		return nil
+	case <-s.closing:
+		return ErrHandlerClosed
+	}
+}
+
+func (s *asyncState) send(ctx context.Context, item asyncItem) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrHandlerClosed
+	}
+	select {
+	case s.queue <- item:
+		return nil
+	case <-s.closing:
+		return ErrHandlerClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
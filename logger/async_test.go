@@ -0,0 +1,300 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingHandler holds every record until release is closed.
+type blockingHandler struct {
+	slog.Handler
+	release chan struct{}
+}
+
+func (h blockingHandler) Handle(ctx context.Context, record slog.Record) error {
+	<-h.release
+	return h.Handler.Handle(ctx, record)
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAsyncHandlerFlushAndClose(t *testing.T) {
+	var buf lockedBuffer
+	h := NewAsyncHandler(slog.NewJSONHandler(&buf, nil), AsyncOptions{QueueSize: 4})
+	l := slog.New(h).With("component", "db")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Info("hello")
+		}()
+	}
+	wg.Wait()
+	if err := h.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := strings.Count(buf.String(), "\"component\":\"db\""); got != 8 {
+		t.Fatalf("records = %d, want 8; output = %q", got, buf.String())
+	}
+
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)); !errors.Is(err, ErrHandlerClosed) {
+		t.Fatalf("Handle() after Close error = %v, want %v", err, ErrHandlerClosed)
+	}
+}
+
+func TestAsyncHandlerOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    AsyncOptions
+		want    []string
+		dropped uint64
+	}{
+		{
+			name:    "drop_newest",
+			opts:    AsyncOptions{QueueSize: 2, Overflow: OverflowDropNewest},
+			want:    []string{"first", "second", "third"},
+			dropped: 1,
+		},
+		{
+			name:    "drop_oldest",
+			opts:    AsyncOptions{QueueSize: 2, Overflow: OverflowDropOldest},
+			want:    []string{"first", "third", "error"},
+			dropped: 1,
+		},
+		{
+			name:    "drop_below_level",
+			opts:    AsyncOptions{QueueSize: 2, Overflow: OverflowDropBelowLevel, DropBelow: slog.LevelError},
+			want:    []string{"first", "second", "third", "error"},
+			dropped: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf lockedBuffer
+			release := make(chan struct{})
+			h := NewAsyncHandler(blockingHandler{Handler: slog.NewJSONHandler(&buf, nil), release: release}, tt.opts)
+
+			// The first record is taken by the writer goroutine and blocks it.
+			handle(t, h, slog.LevelInfo, "first")
+			waitForEmptyQueue(t, h)
+			handle(t, h, slog.LevelInfo, "second")
+			handle(t, h, slog.LevelInfo, "third")
+			blocked := make(chan struct{})
+			if tt.opts.Overflow == OverflowDropBelowLevel {
+				handle(t, h, slog.LevelInfo, "dropped")
+				// Records at or above DropBelow wait for room in the queue.
+				go func() {
+					defer close(blocked)
+					handle(t, h, slog.LevelError, "error")
+				}()
+			} else {
+				handle(t, h, slog.LevelError, "error")
+				close(blocked)
+			}
+			close(release)
+			<-blocked
+			if err := h.Close(context.Background()); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			output := buf.String()
+			for _, msg := range tt.want {
+				if !strings.Contains(output, "\"msg\":\""+msg+"\"") {
+					t.Fatalf("expected %q in output, got %q", msg, output)
+				}
+			}
+			if got := strings.Count(output, "\n"); got != len(tt.want) {
+				t.Fatalf("records = %d, want %d; output = %q", got, len(tt.want), output)
+			}
+			if got := h.Dropped(); got != tt.dropped {
+				t.Fatalf("Dropped() = %d, want %d", got, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestAsyncHandlerDropOldestRequeuesFlushMarker(t *testing.T) {
+	var buf lockedBuffer
+	release := make(chan struct{})
+	h := NewAsyncHandler(blockingHandler{Handler: slog.NewJSONHandler(&buf, nil), release: release},
+		AsyncOptions{QueueSize: 2, Overflow: OverflowDropOldest})
+
+	handle(t, h, slog.LevelInfo, "first")
+	waitForEmptyQueue(t, h)
+	flushed := make(chan error, 1)
+	go func() { flushed <- h.Flush(context.Background()) }()
+	for len(h.state.queue) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	handle(t, h, slog.LevelInfo, "second")
+	// The queue holds the marker and "second": the marker moves to the back,
+	// "second" is dropped, and "third" is queued behind the marker.
+	handle(t, h, slog.LevelInfo, "third")
+	// The writer goroutine is still blocked, so the queue can be inspected.
+	queued := []asyncItem{<-h.state.queue, <-h.state.queue}
+	if queued[0].flush == nil || queued[1].record.Message != "third" {
+		t.Fatalf("expected the flush marker ahead of third, got %+v", queued)
+	}
+	h.state.queue <- queued[0]
+	h.state.queue <- queued[1]
+	close(release)
+	if err := <-flushed; err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	output := buf.String()
+	if strings.Contains(output, "second") || !strings.Contains(output, "third") || h.Dropped() != 1 {
+		t.Fatalf("expected second to be dropped, got %q (dropped %d)", output, h.Dropped())
+	}
+}
+
+func TestAsyncHandlerDropOldestBlocksOnQueuedFlushMarkers(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := NewAsyncHandler(blockingHandler{Handler: slog.NewJSONHandler(io.Discard, nil), release: release},
+		AsyncOptions{QueueSize: 2, Overflow: OverflowDropOldest})
+
+	handle(t, h, slog.LevelInfo, "first")
+	waitForEmptyQueue(t, h)
+	flushed := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { flushed <- h.Flush(context.Background()) }()
+	}
+	for len(h.state.queue) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "second", 0))
+	}()
+
+	select {
+	case err := <-blocked:
+		t.Fatalf("Handle() returned %v, want it to wait for room behind the flush markers", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := h.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close() error = %v, want deadline exceeded", err)
+	}
+	if err := <-blocked; !errors.Is(err, ErrHandlerClosed) {
+		t.Fatalf("blocked Handle() error = %v, want ErrHandlerClosed", err)
+	}
+	// The markers were left in the queue for the blocked writer goroutine.
+	select {
+	case err := <-flushed:
+		t.Fatalf("Flush() returned %v before the queued records were handled", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if h.Dropped() != 0 {
+		t.Fatalf("Dropped() = %d, want 0", h.Dropped())
+	}
+}
+
+func TestAsyncHandlerCloseReleasesBlockedSenders(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := NewAsyncHandler(blockingHandler{Handler: slog.NewJSONHandler(io.Discard, nil), release: release},
+		AsyncOptions{QueueSize: 1})
+
+	handle(t, h, slog.LevelInfo, "first")
+	waitForEmptyQueue(t, h)
+	handle(t, h, slog.LevelInfo, "second")
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "third", 0))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := h.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close() error = %v, want deadline exceeded", err)
+	}
+	if err := <-blocked; !errors.Is(err, ErrHandlerClosed) {
+		t.Fatalf("blocked Handle() error = %v, want ErrHandlerClosed", err)
+	}
+}
+
+func handle(t *testing.T, h slog.Handler, level slog.Level, msg string) {
+	t.Helper()
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), level, msg, 0)); err != nil {
+		t.Errorf("Handle(%q) error = %v", msg, err)
+	}
+}
+
+func waitForEmptyQueue(t *testing.T, h *AsyncHandler) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(h.state.queue) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("queue was not drained")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoggerFlushReachesNestedHandlers(t *testing.T) {
+	var buf lockedBuffer
+	async := NewAsyncHandler(slog.NewJSONHandler(&buf, nil), AsyncOptions{})
+	l := NewWithOptions(WithMultiHandler(Destination{Handler: async}))
+
+	l.With("component", "db").Info("hello")
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "\"msg\":\"hello\"") {
+		t.Fatalf("expected record after Flush, got %q", buf.String())
+	}
+
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := async.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)); !errors.Is(err, ErrHandlerClosed) {
+		t.Fatalf("Handle() after Close error = %v, want %v", err, ErrHandlerClosed)
+	}
+}
+
+func TestPanicFlushesBeforePanicking(t *testing.T) {
+	var buf lockedBuffer
+	l := NewWithOptions(WithHandler(NewAsyncHandler(slog.NewJSONHandler(&buf, nil), AsyncOptions{})))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+		if !strings.Contains(buf.String(), "\"msg\":\"boom\"") {
+			t.Fatalf("expected panic record to be flushed, got %q", buf.String())
+		}
+	}()
+	l.Panic("boom")
+}
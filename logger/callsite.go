@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"log/slog"
+	"math"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// CallSite describes a DEBUG or TRACE logging call site seen by a logger.
+type CallSite struct {
+	Function string // Function name as logged in the source group.
+	File     string // Base name of the file.
+	Line     int
+	// Override reports whether a rule set with EnableCallSites or
+	// DisableCallSites applies to the call site.
+	Override bool
+	// Level is the lowest level the call site logs at when Override is set.
+	Level slog.Level
+}
+
+// CallSiteFilter selects call sites. Empty fields match every call site.
+// File and Function are path.Match patterns, matched against the base file
+// name and the function name as logged in the source group.
+type CallSiteFilter struct {
+	File     string
+	Function string
+	Line     int
+}
+
+func (f CallSiteFilter) match(site *CallSite) bool {
+	if f.File != "" {
+		if ok, _ := path.Match(f.File, site.File); !ok {
+			return false
+		}
+	}
+	if f.Function != "" {
+		if ok, _ := path.Match(f.Function, site.Function); !ok {
+			return false
+		}
+	}
+	return f.Line == 0 || f.Line == site.Line
+}
+
+type callSiteRule struct {
+	filter CallSiteFilter
+	level  slog.Level
+}
+
+// callSites registers DEBUG and TRACE call sites on first use and applies
+// the rules that enable or disable them at runtime.
+type callSites struct {
+	mu     sync.RWMutex
+	sites  map[uintptr]*CallSite
+	order  []*CallSite
+	rules  []callSiteRule
+	lowest slog.LevelVar // Lowest level enabled by a rule.
+}
+
+func newCallSites() *callSites {
+	s := &callSites{sites: make(map[uintptr]*CallSite)}
+	s.lowest.Set(slog.Level(math.MaxInt))
+	return s
+}
+
+// lookup returns the level of the call site calldepth frames up, resolved
+// like source, registering it on first use. ok is false when no rule applies
+// to the call site.
+func (s *callSites) lookup(calldepth int) (level slog.Level, ok bool) {
+	var pc [1]uintptr
+	if runtime.Callers(calldepth, pc[:]) == 0 {
+		return 0, false
+	}
+
+	s.mu.RLock()
+	site, found := s.sites[pc[0]]
+	if found {
+		level, ok = site.Level, site.Override
+	}
+	s.mu.RUnlock()
+	if found {
+		return level, ok
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if site, found = s.sites[pc[0]]; !found {
+		f, _ := runtime.CallersFrames([]uintptr{pc[0]}).Next()
+		site = &CallSite{
+			Function: getFuncName(f.Function),
+			File:     filepath.Base(f.File),
+			Line:     f.Line,
+		}
+		s.apply(site)
+		s.sites[pc[0]] = site
+		s.order = append(s.order, site)
+	}
+	return site.Level, site.Override
+}
+
+func (s *callSites) list() []CallSite {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sites := make([]CallSite, len(s.order))
+	for i, site := range s.order {
+		sites[i] = *site
+	}
+	return sites
+}
+
+func (s *callSites) addRule(filter CallSiteFilter, level slog.Level) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = append(s.rules, callSiteRule{filter: filter, level: level})
+	s.update()
+}
+
+func (s *callSites) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = nil
+	s.update()
+}
+
+// update must be called with s.mu held.
+func (s *callSites) update() {
+	lowest := slog.Level(math.MaxInt)
+	for _, rule := range s.rules {
+		lowest = min(lowest, rule.level)
+	}
+	s.lowest.Set(lowest)
+	for _, site := range s.order {
+		s.apply(site)
+	}
+}
+
+// apply must be called with s.mu held. The last matching rule wins.
+func (s *callSites) apply(site *CallSite) {
+	site.Override, site.Level = false, 0
+	for _, rule := range s.rules {
+		if rule.filter.match(site) {
+			site.Override, site.Level = true, rule.level
+		}
+	}
+}
+
+// CallSites returns the DEBUG and TRACE call sites logged through l and its
+// children, in the order they were first used.
+func (l *Logger) CallSites() []CallSite {
+	return l.root().names.sites.list()
+}
+
+// EnableCallSites makes the call sites matched by filter log at level and
+// above, regardless of the logger level. Rules apply in the order they are
+// added; the last matching rule wins.
+func (l *Logger) EnableCallSites(filter CallSiteFilter, level slog.Level) {
+	l.root().names.sites.addRule(filter, level)
+}
+
+// DisableCallSites stops the call sites matched by filter from logging DEBUG
+// and TRACE records, regardless of the logger level.
+func (l *Logger) DisableCallSites(filter CallSiteFilter) {
+	l.root().names.sites.addRule(filter, slog.LevelInfo)
+}
+
+// ResetCallSites removes every rule added with EnableCallSites and DisableCallSites.
+func (l *Logger) ResetCallSites() {
+	l.root().names.sites.reset()
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func queryDebug(l *Logger) {
+	l.Debug("query")
+}
+
+func poolDebug(l *Logger) {
+	l.Debug("pool")
+}
+
+func TestCallSitesCanBeEnabledAtRuntime(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithJSONHandler(&buf), WithLevel(slog.LevelInfo))
+	// Debug, logWithConfig and the lookup or source frames sit above the call site.
+	l.SetCalldepth(5)
+
+	queryDebug(l)
+	poolDebug(l)
+	if got := buf.String(); got != "" {
+		t.Fatalf("expected debug records to be filtered, got %q", got)
+	}
+
+	sites := l.CallSites()
+	if len(sites) != 2 || sites[0].Function != "queryDebug" || sites[0].File != "callsite_test.go" {
+		t.Fatalf("unexpected call sites %+v", sites)
+	}
+
+	l.EnableCallSites(CallSiteFilter{Function: "query*"}, slog.LevelDebug)
+	queryDebug(l)
+	poolDebug(l)
+	output := buf.String()
+	if !strings.Contains(output, "\"msg\":\"query\"") || strings.Contains(output, "\"msg\":\"pool\"") {
+		t.Fatalf("expected only the enabled call site, got %q", output)
+	}
+	if !strings.Contains(output, "\"func\":\"queryDebug\"") {
+		t.Fatalf("expected source of the call site, got %q", output)
+	}
+
+	buf.Reset()
+	l.SetLevel(slog.LevelDebug)
+	l.DisableCallSites(CallSiteFilter{File: "callsite_test.go", Line: sites[1].Line})
+	queryDebug(l)
+	poolDebug(l)
+	output = buf.String()
+	if !strings.Contains(output, "\"msg\":\"query\"") || strings.Contains(output, "\"msg\":\"pool\"") {
+		t.Fatalf("expected disabled call site to be filtered, got %q", output)
+	}
+
+	buf.Reset()
+	l.ResetCallSites()
+	poolDebug(l)
+	if !strings.Contains(buf.String(), "\"msg\":\"pool\"") {
+		t.Fatalf("expected global level after reset, got %q", buf.String())
+	}
+}
+
+func TestCallSitesFollowCalldepth(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithJSONHandler(&buf), WithLevel(slog.LevelInfo))
+	l.SetCalldepth(6)
+
+	queryDebug(l)
+	sites := l.CallSites()
+	if len(sites) != 1 || sites[0].Function != "TestCallSitesFollowCalldepth" {
+		t.Fatalf("expected the caller of queryDebug, got %+v", sites)
+	}
+
+	l.EnableCallSites(CallSiteFilter{Function: sites[0].Function}, slog.LevelDebug)
+	queryDebug(l)
+	if !strings.Contains(buf.String(), "\"func\":\"TestCallSitesFollowCalldepth\"") {
+		t.Fatalf("expected source at the same depth as the call site, got %q", buf.String())
+	}
+}
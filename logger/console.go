@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ConsoleOptions configures a ConsoleHandler.
+type ConsoleOptions struct {
+	// Level is the minimum level handled. Defaults to INFO.
+	Level slog.Leveler
+	// TimeFormat defaults to "15:04:05.000".
+	TimeFormat string
+	// NoColor disables ANSI colors. Colors are also disabled when the
+	// writer is not a terminal or the NO_COLOR environment variable is set.
+	NoColor bool
+}
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiDim   = "\x1b[2m"
+)
+
+var consoleLevelColors = map[slog.Level]string{
+	LevelTrace:      "\x1b[90m",
+	slog.LevelDebug: "\x1b[36m",
+	slog.LevelInfo:  "\x1b[32m",
+	slog.LevelWarn:  "\x1b[33m",
+	slog.LevelError: "\x1b[31m",
+	LevelFatal:      "\x1b[1;31m",
+	LevelPanic:      "\x1b[1;97;41m",
+}
+
+// ConsoleHandler writes human-readable records for local development:
+//
+//	15:04:05.000 INFO  request served status=200 req.id=7  handler.go:42 (ServeHTTP)
+//
+// Each line starts with the dimmed time and a level badge padded to a common
+// width, followed by the message, the attrs with dotted group keys, and the
+// short source taken from the source group. Stack traces are rendered one
+// frame per line below the record.
+type ConsoleHandler struct {
+	opts   ConsoleOptions
+	color  bool
+	mu     *sync.Mutex
+	w      io.Writer
+	attrs  []byte // Preformatted attrs from WithAttrs.
+	prefix string
+	source string   // Source group from WithAttrs.
+	stack  []string // Stack trace of the source group from WithAttrs.
+}
+
+// NewConsoleHandler creates a ConsoleHandler writing to w.
+func NewConsoleHandler(w io.Writer, opts ConsoleOptions) *ConsoleHandler {
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = "15:04:05.000"
+	}
+	return &ConsoleHandler{
+		opts:  opts,
+		color: !opts.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(w),
+		mu:    &sync.Mutex{},
+		w:     w,
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Enabled reports whether level reaches ConsoleOptions.Level.
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle writes the record as one line, followed by its stack trace if any.
+func (h *ConsoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	if !record.Time.IsZero() {
+		h.paint(&b, ansiDim, record.Time.Format(h.opts.TimeFormat))
+		b.WriteByte(' ')
+	}
+	h.paint(&b, consoleLevelColor(record.Level), fmt.Sprintf("%-5s", consoleLevelName(record.Level)))
+	b.WriteByte(' ')
+	b.WriteString(record.Message)
+	b.Write(h.attrs)
+
+	source, stack := h.source, h.stack
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "source" && attr.Value.Kind() == slog.KindGroup {
+			source, stack = consoleSource(attr.Value.Group())
+			return true
+		}
+		h.appendAttr(&b, attr)
+		return true
+	})
+	if source != "" {
+		b.WriteString("  ")
+		h.paint(&b, ansiDim, source)
+	}
+	b.WriteByte('\n')
+	for _, frame := range stack {
+		b.WriteString("    ")
+		h.paint(&b, ansiDim, "at "+frame)
+		b.WriteByte('\n')
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs returns a ConsoleHandler sharing the writer of h.
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	var b strings.Builder
+	b.Write(h.attrs)
+	for _, attr := range attrs {
+		if h.prefix == "" && attr.Key == "source" && attr.Value.Kind() == slog.KindGroup {
+			clone.source, clone.stack = consoleSource(attr.Value.Group())
+			continue
+		}
+		h.appendAttr(&b, attr)
+	}
+	clone.attrs = []byte(b.String())
+	return &clone
+}
+
+// WithGroup returns a ConsoleHandler sharing the writer of h.
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+func (h *ConsoleHandler) appendAttr(b *strings.Builder, attr slog.Attr) {
+	for _, flat := range appendFlatAttr(nil, h.prefix, attr) {
+		value := attrString(flat.Value)
+		if value == "" || strings.ContainsAny(value, " \"=\\\n\t") {
+			value = strconv.Quote(value)
+		}
+		b.WriteByte(' ')
+		h.paint(b, ansiDim, flat.Key+"=")
+		b.WriteString(value)
+	}
+}
+
+func (h *ConsoleHandler) paint(b *strings.Builder, color, s string) {
+	if !h.color || color == "" {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(color + s + ansiReset)
+}
+
+// consoleSource returns the source group as "file:line (func)" and the
+// frames of its stack trace.
+func consoleSource(attrs []slog.Attr) (string, []string) {
+	var function, file, line string
+	var stack []string
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "func":
+			function = attr.Value.String()
+		case "file":
+			file = attr.Value.String()
+		case "line":
+			line = attr.Value.String()
+		case "stack_trace":
+			for _, frame := range attr.Value.Group() {
+				stack = append(stack, frame.Value.String())
+			}
+		}
+	}
+	source := file
+	if line != "" {
+		source += ":" + line
+	}
+	if function != "" {
+		source += " (" + function + ")"
+	}
+	return strings.TrimSpace(source), stack
+}
+
+func consoleLevelName(level slog.Level) string {
+	if name, ok := LevelNames[level]; ok {
+		return name
+	}
+	return level.String()
+}
+
+// consoleLevelColor returns the color of the highest named level not above level.
+func consoleLevelColor(level slog.Level) string {
+	color := consoleLevelColors[LevelTrace]
+	best := LevelTrace
+	for l, c := range consoleLevelColors {
+		if l <= level && l >= best {
+			best, color = l, c
+		}
+	}
+	return color
+}
+
+// SetConsoleHandler configures the logger to emit colorized, human-readable
+// logs to the provided writer.
+func (l *Logger) SetConsoleHandler(w io.Writer) {
+	l.setBackend(slog.New(NewConsoleHandler(w, ConsoleOptions{Level: l.root().names})))
+}
+
+// WithConsoleHandler configures a console handler that writes to w.
+func WithConsoleHandler(w io.Writer) Option {
+	return func(l *Logger) {
+		l.SetConsoleHandler(w)
+	}
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleHandlerFormatsPlainLines(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithConsoleHandler(&buf), WithLevel(LevelTrace))
+	l.SetCalldepth(6)
+
+	l.With("service", "api").WithGroup("req").Info("served", "id", 7, "path", "/a b", "empty", "")
+	l.Print("tracing")
+	line := `\d\d:\d\d:\d\d\.\d{3} `
+	want := []*regexp.Regexp{
+		regexp.MustCompile(`^` + line + `INFO  served service=api req\.id=7 req\.path="/a b" req\.empty=""  console_test\.go:\d+ \(TestConsoleHandlerFormatsPlainLines\)$`),
+		regexp.MustCompile(`^` + line + `TRACE tracing  console_test\.go:\d+ \(TestConsoleHandlerFormatsPlainLines\)$`),
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(got), len(want), buf.String())
+	}
+	for i, re := range want {
+		if !re.MatchString(got[i]) {
+			t.Errorf("line %d = %q, want match %s", i, got[i], re)
+		}
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("output to a buffer has colors: %q", buf.String())
+	}
+}
+
+func TestConsoleHandlerRendersStackTraceOnePerLine(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithConsoleHandler(&buf), WithLevel(slog.LevelDebug), WithDebugStackTrace(true))
+	l.SetCalldepth(5)
+
+	l.Debug("stack")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("output = %q, want record and stack frames", buf.String())
+	}
+	if strings.Contains(lines[0], "stack_trace") {
+		t.Errorf("first line = %q, want stack trace rendered below", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "    at console_test.go:") {
+		t.Errorf("first frame = %q, want the caller frame", lines[1])
+	}
+}
+
+func TestConsoleHandlerColorsLevels(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, ConsoleOptions{Level: LevelTrace, TimeFormat: time.Kitchen})
+	h.color = true
+
+	tests := []struct {
+		level slog.Level
+		badge string
+	}{
+		{LevelTrace, "\x1b[90mTRACE\x1b[0m"},
+		{slog.LevelInfo, "\x1b[32mINFO \x1b[0m"},
+		{slog.LevelWarn + 1, "\x1b[33mWARN+1\x1b[0m"},
+		{LevelFatal, "\x1b[1;31mFATAL\x1b[0m"},
+		{LevelPanic, "\x1b[1;97;41mPANIC\x1b[0m"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		record := slog.NewRecord(time.Date(2024, 1, 1, 15, 4, 0, 0, time.UTC), tt.level, "msg", 0)
+		record.AddAttrs(slog.Int("n", 1))
+		if err := h.Handle(context.Background(), record); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+		want := "\x1b[2m3:04PM\x1b[0m " + tt.badge + " msg \x1b[2mn=\x1b[0m1\n"
+		if buf.String() != want {
+			t.Errorf("level %v output = %q, want %q", tt.level, buf.String(), want)
+		}
+	}
+}
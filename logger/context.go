@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type attrsKey struct{}
+
+// AddAttrs returns a copy of ctx carrying attrs after any attributes
+// previously added to it. Loggers append them to every record logged with
+// the returned context.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev := ContextAttrs(ctx)
+	next := make([]slog.Attr, 0, len(prev)+len(attrs))
+	next = append(next, prev...)
+	next = append(next, attrs...)
+	return context.WithValue(ctx, attrsKey{}, next)
+}
+
+// ContextAttrs returns the attributes added to ctx with AddAttrs.
+// The returned slice must not be modified.
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+func appendContextAttrs(ctx context.Context, args []any) []any {
+	attrs := ContextAttrs(ctx)
+	if len(attrs) == 0 {
+		return args
+	}
+	next := make([]any, 0, len(args)+len(attrs)+1)
+	next = append(next, args...)
+	for _, attr := range attrs {
+		next = append(next, attr)
+	}
+	return next
+}
@@ -2,6 +2,8 @@ package logger
 
 import (
 	"log/slog"
+	"reflect"
+	"unsafe"
 )
 
 const (
@@ -33,3 +35,69 @@ func ReplaceAttr(_ []string, attr slog.Attr) slog.Attr {
 
 	return attr
 }
+
+// withLevelNames returns a copy of h that renders the names in LevelNames,
+// for the handlers whose options accept a ReplaceAttr: slog.JSONHandler,
+// slog.TextHandler and LogfmtHandler. Other handlers are returned unchanged.
+func withLevelNames(h slog.Handler) slog.Handler {
+	switch h := h.(type) {
+	case *LogfmtHandler:
+		clone := *h
+		clone.opts.ReplaceAttr = replaceLevelNames(h.opts.ReplaceAttr)
+		return &clone
+	case *slog.JSONHandler, *slog.TextHandler:
+		if named, ok := slogHandlerWithLevelNames(h); ok {
+			return named
+		}
+	}
+	return h
+}
+
+// replaceLevelNames returns a ReplaceAttr that calls next, then renames the
+// custom levels of the record level that next left as a slog.Level.
+func replaceLevelNames(next func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if next != nil {
+			attr = next(groups, attr)
+		}
+		if len(groups) > 0 {
+			return attr
+		}
+		return ReplaceAttr(groups, attr)
+	}
+}
+
+// slogHandlerWithLevelNames clones a slog.JSONHandler or slog.TextHandler
+// with replaceLevelNames added to its options. slog cannot change the options
+// of a built handler, so the clone copies its unexported state the way its
+// WithAttrs does. ok is false when that state does not have the known layout.
+func slogHandlerWithLevelNames(h slog.Handler) (named slog.Handler, ok bool) {
+	v := reflect.ValueOf(h)
+	if v.IsNil() || v.Elem().NumField() != 1 || !v.Elem().Type().Field(0).Anonymous {
+		return nil, false
+	}
+	common := v.Elem().Field(0)
+	if common.Kind() != reflect.Pointer || common.IsNil() || common.Type().Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	opts, found := common.Type().Elem().FieldByName("opts")
+	if !found || opts.Type != reflect.TypeOf(slog.HandlerOptions{}) {
+		return nil, false
+	}
+
+	clone := reflect.New(common.Type().Elem())
+	clone.Elem().Set(reflect.NewAt(common.Type().Elem(), common.UnsafePointer()).Elem())
+	for i := 0; i < clone.Elem().NumField(); i++ {
+		// Clip the slices shared with h so that appends do not overwrite each other.
+		if field := clone.Elem().Field(i); field.Kind() == reflect.Slice {
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+			field.Set(field.Slice3(0, field.Len(), field.Len()))
+		}
+	}
+	options := (*slog.HandlerOptions)(unsafe.Add(clone.UnsafePointer(), opts.Offset))
+	options.ReplaceAttr = replaceLevelNames(options.ReplaceAttr)
+
+	out := reflect.New(v.Type().Elem())
+	reflect.NewAt(common.Type(), unsafe.Pointer(out.Elem().Field(0).UnsafeAddr())).Elem().Set(clone)
+	return out.Interface().(slog.Handler), true
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DatadogOptions configures a Datadog handler.
+type DatadogOptions struct {
+	// Service, Env and Version are written as dd.service, dd.env and
+	// dd.version for unified service tagging. They default to the DD_SERVICE,
+	// DD_ENV and DD_VERSION environment variables.
+	Service string
+	Env     string
+	Version string
+	// Level is the minimum level handled. Defaults to INFO.
+	Level slog.Leveler
+}
+
+// NewDatadogHandler returns a handler writing JSON with Datadog reserved
+// attributes: timestamp, status, message; dd.trace_id and dd.span_id as the
+// decimal lower 64 bits of the trace_id and span_id attrs of the otel
+// handler, or of the span in the record context; the logger name and the
+// source group as logger.name, logger.method_name, logger.file_name and
+// logger.line; and the error attr as error.kind, error.message and
+// error.stack.
+func NewDatadogHandler(w io.Writer, opts DatadogOptions) slog.Handler {
+	if opts.Service == "" {
+		opts.Service = os.Getenv("DD_SERVICE")
+	}
+	if opts.Env == "" {
+		opts.Env = os.Getenv("DD_ENV")
+	}
+	if opts.Version == "" {
+		opts.Version = os.Getenv("DD_VERSION")
+	}
+	return newShapeHandler(w, opts.Level, &recordShape{
+		replaceAttr: datadogReplaceAttr,
+		lift: map[string]func(slog.Value) bool{
+			"source": isGroup, "error": isError, NameKey: isString,
+			// Injected by otel.OtelHandler as otel.TraceIDKey and otel.SpanIDKey.
+			"trace_id": isTraceID, "span_id": isSpanID,
+		},
+		fields: func(ctx context.Context, _ slog.Record, lifted map[string]slog.Value) []slog.Attr {
+			return datadogFields(ctx, opts, lifted)
+		},
+	})
+}
+
+// DatadogStatus maps a level to a Datadog log status.
+func DatadogStatus(level slog.Level) string {
+	switch {
+	case level >= LevelPanic:
+		return "alert"
+	case level >= LevelFatal:
+		return "critical"
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level > slog.LevelInfo:
+		return "notice"
+	case level == slog.LevelInfo:
+		return "info"
+	}
+	return "debug"
+}
+
+// DatadogID converts a hex OpenTelemetry trace or span ID to the decimal
+// form of its lower 64 bits used by Datadog. It returns false when id is
+// not a valid hex ID.
+func DatadogID(id string) (string, bool) {
+	if len(id) > 16 {
+		id = id[len(id)-16:]
+	}
+	n, err := strconv.ParseUint(id, 16, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatUint(n, 10), true
+}
+
+func datadogReplaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return attr
+	}
+	switch attr.Key {
+	case slog.TimeKey:
+		attr.Key = "timestamp"
+	case slog.LevelKey:
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			return slog.String("status", DatadogStatus(level))
+		}
+	case slog.MessageKey:
+		attr.Key = "message"
+	}
+	return attr
+}
+
+func datadogFields(ctx context.Context, opts DatadogOptions, lifted map[string]slog.Value) []slog.Attr {
+	var traceID, spanID string
+	if id, ok := lifted["trace_id"]; ok {
+		traceID = id.String()
+	}
+	if id, ok := lifted["span_id"]; ok {
+		spanID = id.String()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() && traceID == "" {
+		traceID, spanID = sc.TraceID().String(), sc.SpanID().String()
+	}
+	var dd []any
+	if id, ok := DatadogID(traceID); ok {
+		dd = append(dd, slog.String("trace_id", id))
+	}
+	if id, ok := DatadogID(spanID); ok {
+		dd = append(dd, slog.String("span_id", id))
+	}
+	for _, tag := range []struct{ key, value string }{
+		{"service", opts.Service}, {"env", opts.Env}, {"version", opts.Version},
+	} {
+		if tag.value != "" {
+			dd = append(dd, slog.String(tag.key, tag.value))
+		}
+	}
+
+	var fields []slog.Attr
+	if len(dd) > 0 {
+		fields = append(fields, slog.Group("dd", dd...))
+	}
+
+	var log []any
+	if name, ok := lifted[NameKey]; ok {
+		log = append(log, slog.String("name", name.String()))
+	}
+	if source := groupMembers(lifted["source"]); source != nil {
+		if function, ok := source["func"]; ok {
+			log = append(log, slog.String("method_name", function.String()))
+		}
+		if file, ok := source["file"]; ok {
+			log = append(log, slog.String("file_name", file.String()))
+		}
+		if line, ok := source["line"]; ok {
+			log = append(log, slog.Any("line", line))
+		}
+	}
+	if len(log) > 0 {
+		fields = append(fields, slog.Group("logger", log...))
+	}
+
+	if err, ok := lifted["error"]; ok {
+		details := ParseErrorAttr(err)
+		attrs := []any{slog.String("message", details.Message)}
+		if kind := details.Code; kind != "" {
+			attrs = append(attrs, slog.String("kind", kind))
+		} else if details.Type != "" {
+			attrs = append(attrs, slog.String("kind", details.Type))
+		}
+		if details.Stack != "" {
+			attrs = append(attrs, slog.String("stack", details.Stack))
+		}
+		fields = append(fields, slog.Group("error", attrs...))
+	}
+	return fields
+}
+
+// SetDatadogHandler configures the logger to emit JSON with Datadog reserved
+// attributes to the provided writer. A nil opts.Level follows the logger
+// level.
+func (l *Logger) SetDatadogHandler(w io.Writer, opts DatadogOptions) {
+	if opts.Level == nil {
+		opts.Level = l.root().names
+	}
+	l.setBackend(slog.New(NewDatadogHandler(w, opts)))
+}
+
+// WithDatadogHandler configures a Datadog handler that writes to w.
+func WithDatadogHandler(w io.Writer, opts DatadogOptions) Option {
+	return func(l *Logger) {
+		l.SetDatadogHandler(w, opts)
+	}
+}
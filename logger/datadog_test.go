@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestDatadogHandlerMapsReservedAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithDatadogHandler(&buf, DatadogOptions{Service: "billing", Env: "prod"})).Named("payments")
+	l.SetCalldepth(6)
+
+	l.ErrorContext(testSpanContext(t), "charge failed", "error", errors.New("card declined"), "id", 7)
+	got := decodeJSONLines(t, &buf)[0]
+
+	for key, want := range map[string]any{
+		"status":  "error",
+		"message": "charge failed",
+		"dd": map[string]any{
+			"trace_id": "9532127138774266268",
+			"span_id":  "13235353014750950193",
+			"service":  "billing",
+			"env":      "prod",
+		},
+		"error": map[string]any{"message": "card declined", "kind": "*errors.errorString"},
+		"id":    float64(7),
+	} {
+		if !reflect.DeepEqual(got[key], want) {
+			t.Errorf("%s = %#v, want %#v", key, got[key], want)
+		}
+	}
+	if got["timestamp"] == nil || got["level"] != nil || got["source"] != nil {
+		t.Errorf("record = %v, want timestamp and status without level or source", got)
+	}
+	log, _ := got["logger"].(map[string]any)
+	if log["name"] != "payments" || log["method_name"] != "TestDatadogHandlerMapsReservedAttributes" ||
+		log["file_name"] != "datadog_test.go" || log["line"] == nil {
+		t.Errorf("logger = %v, want name and source fields", got["logger"])
+	}
+}
+
+func TestDatadogHandlerKeepsAttrsOfOtherShapes(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewDatadogHandler(&buf, DatadogOptions{})).Info("msg",
+		"error", "x", NameKey, 7, "source", "cache", "trace_id", "abc", "span_id", "def")
+	got := decodeJSONLines(t, &buf)[0]
+
+	for key, want := range map[string]any{
+		"error": "x", NameKey: float64(7), "source": "cache", "trace_id": "abc", "span_id": "def", "dd": nil,
+	} {
+		if !reflect.DeepEqual(got[key], want) {
+			t.Errorf("%s = %#v, want %#v", key, got[key], want)
+		}
+	}
+}
+
+func TestDatadogIDUsesLowerBits(t *testing.T) {
+	tests := []struct {
+		id, want string
+		ok       bool
+	}{
+		{"0af7651916cd43dd8448eb211c80319c", "9532127138774266268", true},
+		{"00000000000000000000000000000001", "1", true},
+		{"b7ad6b7169203331", "13235353014750950193", true},
+		{"not-hex", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := DatadogID(tt.id); got != tt.want || ok != tt.ok {
+			t.Errorf("DatadogID(%q) = %q, %v, want %q, %v", tt.id, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSetDatadogHandlerKeepsCallerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithDatadogHandler(&buf, DatadogOptions{Level: slog.LevelWarn}), WithLevel(slog.LevelDebug))
+
+	l.Info("hidden")
+	l.Warn("visible")
+	if records := decodeJSONLines(t, &buf); len(records) != 1 || records[0]["message"] != "visible" {
+		t.Fatalf("records = %v, want only the warning", records)
+	}
+}
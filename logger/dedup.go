@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RepeatedKey is the key used by dedup summaries for the number of suppressed records.
+	RepeatedKey = "repeated"
+	// FirstSeenKey is the key used by dedup summaries for the time of the first record.
+	FirstSeenKey = "first_seen"
+	// LastSeenKey is the key used by dedup summaries for the time of the last suppressed record.
+	LastSeenKey = "last_seen"
+)
+
+// DedupOptions configures a DedupHandler.
+type DedupOptions struct {
+	// Window is how long repeats of a record are suppressed after it is first
+	// logged. Defaults to ten seconds.
+	Window time.Duration
+	// Keys selects the attributes that, along with the level and message,
+	// identify a record. Dotted keys select attributes inside groups, so
+	// "error.code" selects the code of the error group logged for
+	// *errors.Error values.
+	Keys []string
+	// MaxEntries bounds the number of open windows. Records of a new kind
+	// are logged without suppression while it is reached. Defaults to 10000.
+	MaxEntries int
+}
+
+type dedupEntry struct {
+	record    slog.Record // First record, used to build the summary.
+	next      slog.Handler
+	ctx       context.Context
+	repeated  int
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// dedupState is shared by a DedupHandler and the handlers derived from it.
+type dedupState struct {
+	opts    DedupOptions
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*dedupEntry // By fingerprint.
+	expiry  time.Time              // Earliest time an entry window closes.
+	timer   *time.Timer            // Fires at expiry to log the summaries.
+	closed  bool
+}
+
+// DedupHandler suppresses repeated records. The first record of a kind is
+// logged right away; repeats within the window are counted and reported as a
+// single summary carrying RepeatedKey, FirstSeenKey and LastSeenKey when the
+// window closes or Flush is called. Handlers derived with WithAttrs and
+// WithGroup share the windows of h, so a record repeated through several
+// children is suppressed as well.
+type DedupHandler struct {
+	next  slog.Handler
+	state *dedupState
+}
+
+// NewDedupHandler creates a DedupHandler that sends records to next.
+func NewDedupHandler(next slog.Handler, opts DedupOptions) *DedupHandler {
+	if opts.Window <= 0 {
+		opts.Window = 10 * time.Second
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	return &DedupHandler{
+		next: next,
+		state: &dedupState{
+			opts:    opts,
+			now:     time.Now,
+			entries: make(map[string]*dedupEntry),
+		},
+	}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle sends the record to the next handler unless it repeats a record
+// logged within the window.
+func (h *DedupHandler) Handle(ctx context.Context, record slog.Record) error {
+	key := h.state.fingerprint(record)
+	now := h.state.now()
+
+	h.state.mu.Lock()
+	expired := h.state.expire(now)
+	entry, found := h.state.entries[key]
+	if found {
+		entry.repeated++
+		entry.lastSeen = now
+	} else if len(h.state.entries) < h.state.opts.MaxEntries {
+		h.state.entries[key] = &dedupEntry{
+			record:    record.Clone(),
+			next:      h.next,
+			ctx:       context.WithoutCancel(ctx),
+			firstSeen: now,
+			lastSeen:  now,
+		}
+		if h.state.expiry.IsZero() || now.Add(h.state.opts.Window).Before(h.state.expiry) {
+			h.state.expiry = now.Add(h.state.opts.Window)
+		}
+	}
+	h.state.schedule(now)
+	h.state.mu.Unlock()
+
+	err := summarize(expired)
+	if found {
+		return err
+	}
+	return errors.Join(err, h.next.Handle(ctx, record))
+}
+
+// Flush closes every open window, logging the summaries of suppressed
+// records, and flushes the next handler.
+func (h *DedupHandler) Flush(ctx context.Context) error {
+	h.state.mu.Lock()
+	entries := make([]*dedupEntry, 0, len(h.state.entries))
+	for key, entry := range h.state.entries {
+		entries = append(entries, entry)
+		delete(h.state.entries, key)
+	}
+	h.state.expiry = time.Time{}
+	h.state.schedule(time.Time{})
+	h.state.mu.Unlock()
+	return errors.Join(summarize(entries), FlushHandler(ctx, h.next))
+}
+
+// Close stops the window timer, logs the summaries of suppressed records and
+// closes the next handler.
+func (h *DedupHandler) Close(ctx context.Context) error {
+	h.state.mu.Lock()
+	h.state.closed = true
+	h.state.mu.Unlock()
+	err := h.Flush(ctx)
+	return errors.Join(err, CloseHandler(ctx, h.next))
+}
+
+// WithAttrs returns a DedupHandler sharing the windows of h.
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DedupHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup returns a DedupHandler sharing the windows of h.
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	return &DedupHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+func (s *dedupState) fingerprint(record slog.Record) string {
+	var b strings.Builder
+	b.WriteString(record.Level.String())
+	b.WriteByte(0)
+	b.WriteString(record.Message)
+	for _, key := range s.opts.Keys {
+		b.WriteByte(0)
+		if value, found := lookupAttr(record, key); found {
+			b.WriteString(value.String())
+		}
+	}
+	return b.String()
+}
+
+// expire removes the entries whose window closed and must be called with s.mu held.
+func (s *dedupState) expire(now time.Time) []*dedupEntry {
+	if s.expiry.IsZero() || now.Before(s.expiry) {
+		return nil
+	}
+	var expired []*dedupEntry
+	s.expiry = time.Time{}
+	for key, entry := range s.entries {
+		closes := entry.firstSeen.Add(s.opts.Window)
+		if !now.Before(closes) {
+			expired = append(expired, entry)
+			delete(s.entries, key)
+			continue
+		}
+		if s.expiry.IsZero() || closes.Before(s.expiry) {
+			s.expiry = closes
+		}
+	}
+	return expired
+}
+
+// schedule arms the timer for the earliest window to close, or stops it when
+// no window is open. It must be called with s.mu held.
+func (s *dedupState) schedule(now time.Time) {
+	if s.expiry.IsZero() || s.closed {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		return
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.expiry.Sub(now), s.fire)
+		return
+	}
+	s.timer.Reset(s.expiry.Sub(now))
+}
+
+// fire logs the summaries of the windows that closed.
+func (s *dedupState) fire() {
+	now := s.now()
+	s.mu.Lock()
+	expired := s.expire(now)
+	s.schedule(now)
+	s.mu.Unlock()
+	summarize(expired)
+}
+
+func summarize(entries []*dedupEntry) error {
+	var errs []error
+	for _, entry := range entries {
+		if entry.repeated == 0 {
+			continue
+		}
+		summary := entry.record.Clone()
+		summary.Time = entry.lastSeen
+		summary.AddAttrs(
+			slog.Int(RepeatedKey, entry.repeated),
+			slog.Time(FirstSeenKey, entry.firstSeen),
+			slog.Time(LastSeenKey, entry.lastSeen),
+		)
+		if err := entry.next.Handle(entry.ctx, summary); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookupAttr returns the value of the attribute selected by a dotted key.
+func lookupAttr(record slog.Record, key string) (slog.Value, bool) {
+	name, rest, nested := strings.Cut(key, ".")
+	var value slog.Value
+	var found bool
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == name {
+			value, found = attr.Value.Resolve(), true
+			return false
+		}
+		return true
+	})
+	for found && nested {
+		if value.Kind() != slog.KindGroup {
+			return slog.Value{}, false
+		}
+		name, rest, nested = strings.Cut(rest, ".")
+		found = false
+		for _, attr := range value.Group() {
+			if attr.Key == name {
+				value, found = attr.Value.Resolve(), true
+				break
+			}
+		}
+	}
+	return value, found
+}
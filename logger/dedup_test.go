@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupHandlerSummarizesRepeatsWhenWindowCloses(t *testing.T) {
+	var buf bytes.Buffer
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), DedupOptions{Window: time.Minute, Keys: []string{"peer"}})
+	h.state.now = clock.Now
+	l := slog.New(h)
+
+	for i := 0; i < 3; i++ {
+		l.Error("connection refused", "peer", "db-1")
+	}
+	l.Error("connection refused", "peer", "db-2")
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Fatalf("records = %d, want 2; output = %q", got, buf.String())
+	}
+
+	buf.Reset()
+	clock.now = clock.now.Add(time.Minute)
+	l.Error("connection refused", "peer", "db-1")
+	output := buf.String()
+	if !strings.Contains(output, "\"peer\":\"db-1\",\"repeated\":2,\"first_seen\":\"1970-01-01T00:00:00Z\"") {
+		t.Fatalf("expected summary of db-1 repeats, got %q", output)
+	}
+	if strings.Contains(output, "db-2") {
+		t.Fatalf("expected no summary without repeats, got %q", output)
+	}
+	if got := strings.Count(output, "\n"); got != 2 {
+		t.Fatalf("records = %d, want summary and new record; output = %q", got, output)
+	}
+}
+
+func TestDedupHandlerFlushReportsOpenWindows(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), DedupOptions{})
+	l := slog.New(h)
+
+	l.Warn("slow")
+	l.Warn("slow")
+	if err := h.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "\"repeated\":1") {
+		t.Fatalf("expected summary after flush, got %q", buf.String())
+	}
+}
+
+func TestDedupHandlerSummarizesOnTimer(t *testing.T) {
+	var buf lockedBuffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), DedupOptions{Window: 10 * time.Millisecond})
+	defer h.Close(context.Background())
+	l := slog.New(h)
+
+	l.Error("connection refused")
+	l.Error("connection refused")
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "\"repeated\":1") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !strings.Contains(buf.String(), "\"repeated\":1") {
+		t.Fatalf("expected summary when the window closes, got %q", buf.String())
+	}
+}
+
+func TestDedupHandlerSharesWindowsAcrossChildren(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), DedupOptions{Window: time.Minute})
+	defer h.Close(context.Background())
+	l := slog.New(h)
+
+	l.With("request_id", "a").Error("connection refused")
+	l.With("request_id", "b").Error("connection refused")
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Fatalf("records = %d, want 1; output = %q", got, buf.String())
+	}
+}
+
+func TestDedupHandlerBoundsEntries(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), DedupOptions{Window: time.Minute, MaxEntries: 1})
+	defer h.Close(context.Background())
+	l := slog.New(h)
+
+	l.Error("first")
+	l.Error("second")
+	l.Error("second")
+	if got := len(h.state.entries); got != 1 {
+		t.Fatalf("entries = %d, want 1", got)
+	}
+	if got := strings.Count(buf.String(), "\"msg\":\"second\""); got != 2 {
+		t.Fatalf("expected untracked records to be logged, got %q", buf.String())
+	}
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ECSVersion is the Elastic Common Schema version written as ecs.version.
+const ECSVersion = "8.11.0"
+
+// ECSOptions configures an Elastic Common Schema handler.
+type ECSOptions struct {
+	// Level is the minimum level handled. Defaults to INFO.
+	Level slog.Leveler
+}
+
+// NewECSHandler returns a handler writing JSON with Elastic Common Schema
+// field names: @timestamp, log.level, message and ecs.version; the source
+// group as log.origin; the logger name as log.logger; the error attr as
+// error.code, error.message, error.type and error.stack_trace; and the
+// trace_id and span_id attrs of the otel handler, or the span in the record
+// context, as trace.id and span.id.
+func NewECSHandler(w io.Writer, opts ECSOptions) slog.Handler {
+	return newShapeHandler(w, opts.Level, &recordShape{
+		replaceAttr: ecsReplaceAttr,
+		lift: map[string]func(slog.Value) bool{
+			"source": isGroup, "error": isError, NameKey: isString,
+			// Injected by otel.OtelHandler as otel.TraceIDKey and otel.SpanIDKey.
+			"trace_id": isTraceID, "span_id": isSpanID,
+		},
+		fields: ecsFields,
+	})
+}
+
+func ecsReplaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return attr
+	}
+	switch attr.Key {
+	case slog.TimeKey:
+		attr.Key = "@timestamp"
+	case slog.LevelKey:
+		attr = ReplaceAttr(groups, attr)
+		attr.Key = "log.level"
+	case slog.MessageKey:
+		attr.Key = "message"
+	}
+	return attr
+}
+
+func ecsFields(ctx context.Context, _ slog.Record, lifted map[string]slog.Value) []slog.Attr {
+	fields := []slog.Attr{slog.String("ecs.version", ECSVersion)}
+
+	var log []any
+	if name, ok := lifted[NameKey]; ok {
+		log = append(log, slog.String("logger", name.String()))
+	}
+	if source := groupMembers(lifted["source"]); source != nil {
+		var file []any
+		if name, ok := source["file"]; ok {
+			file = append(file, slog.String("name", name.String()))
+		}
+		if line, ok := source["line"]; ok {
+			file = append(file, slog.Any("line", line))
+		}
+		origin := []any{slog.Group("file", file...)}
+		if function, ok := source["func"]; ok {
+			origin = append(origin, slog.String("function", function.String()))
+		}
+		log = append(log, slog.Group("origin", origin...))
+	}
+	if len(log) > 0 {
+		fields = append(fields, slog.Group("log", log...))
+	}
+
+	if err, ok := lifted["error"]; ok {
+		fields = append(fields, slog.Group("error", ecsError(err)...))
+	}
+
+	traceID, spanID := lifted["trace_id"], lifted["span_id"]
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() && traceID.Equal(slog.Value{}) {
+		traceID, spanID = slog.StringValue(sc.TraceID().String()), slog.StringValue(sc.SpanID().String())
+	}
+	if !traceID.Equal(slog.Value{}) {
+		fields = append(fields, slog.Group("trace", slog.String("id", traceID.String())))
+	}
+	if !spanID.Equal(slog.Value{}) {
+		fields = append(fields, slog.Group("span", slog.String("id", spanID.String())))
+	}
+	return fields
+}
+
+// ecsError maps the error attr to the ECS error fields.
+func ecsError(v slog.Value) []any {
+	details := ParseErrorAttr(v)
+	var attrs []any
+	if details.Code != "" {
+		attrs = append(attrs, slog.String("code", details.Code))
+	}
+	attrs = append(attrs, slog.String("message", details.Message))
+	if details.Type != "" {
+		attrs = append(attrs, slog.String("type", details.Type))
+	}
+	if details.Stack != "" {
+		attrs = append(attrs, slog.String("stack_trace", details.Stack))
+	}
+	return attrs
+}
+
+// SetECSHandler configures the logger to emit JSON with Elastic Common
+// Schema field names to the provided writer.
+func (l *Logger) SetECSHandler(w io.Writer) {
+	l.setBackend(slog.New(NewECSHandler(w, ECSOptions{Level: l.root().names})))
+}
+
+// WithECSHandler configures an Elastic Common Schema handler that writes to w.
+func WithECSHandler(w io.Writer) Option {
+	return func(l *Logger) {
+		l.SetECSHandler(w)
+	}
+}
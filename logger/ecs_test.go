@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestECSHandlerMapsFieldNames(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithECSHandler(&buf), WithLevel(LevelTrace)).Named("billing")
+	l.SetCalldepth(6)
+
+	l.WithGroup("req").PrintContext(testSpanContext(t), "charged", "id", 7, "error", errors.New("card declined"))
+	got := decodeJSONLines(t, &buf)[0]
+
+	for key, want := range map[string]any{
+		"log.level":   "TRACE",
+		"message":     "charged",
+		"ecs.version": ECSVersion,
+		"trace":       map[string]any{"id": "0af7651916cd43dd8448eb211c80319c"},
+		"span":        map[string]any{"id": "b7ad6b7169203331"},
+		"error":       map[string]any{"message": "card declined", "type": "*errors.errorString"},
+		"req":         map[string]any{"id": float64(7)},
+	} {
+		if !reflect.DeepEqual(got[key], want) {
+			t.Errorf("%s = %#v, want %#v", key, got[key], want)
+		}
+	}
+	if got["@timestamp"] == nil || got["time"] != nil || got["level"] != nil || got["msg"] != nil {
+		t.Errorf("record = %v, want ECS names for the built-in fields", got)
+	}
+	log, _ := got["log"].(map[string]any)
+	origin, _ := log["origin"].(map[string]any)
+	file, _ := origin["file"].(map[string]any)
+	if log["logger"] != "billing" || origin["function"] != "TestECSHandlerMapsFieldNames" ||
+		file["name"] != "ecs_test.go" || file["line"] == nil {
+		t.Errorf("log = %v, want logger and origin fields", got["log"])
+	}
+}
+
+func TestECSHandlerKeepsAttrsOfOtherShapes(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewECSHandler(&buf, ECSOptions{})).Info("msg",
+		"error", "x", NameKey, 7, "source", "cache", "trace_id", "abc", "span_id", "def")
+	got := decodeJSONLines(t, &buf)[0]
+
+	for key, want := range map[string]any{
+		"error": "x", NameKey: float64(7), "source": "cache", "trace_id": "abc", "span_id": "def",
+		"log": nil, "trace": nil, "span": nil,
+	} {
+		if !reflect.DeepEqual(got[key], want) {
+			t.Errorf("%s = %#v, want %#v", key, got[key], want)
+		}
+	}
+}
+
+func TestECSHandlerUsesOtelAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewECSHandler(&buf, ECSOptions{})
+
+	NewWithOptions(WithHandler(h), WithSource(false)).InfoContext(context.Background(), "msg",
+		"trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7")
+	got := decodeJSONLines(t, &buf)[0]
+	if got["trace"].(map[string]any)["id"] != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		got["span"].(map[string]any)["id"] != "00f067aa0ba902b7" || got["trace_id"] != nil {
+		t.Fatalf("record = %v, want trace_id and span_id as trace.id and span.id", got)
+	}
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ErrorDetails holds the parts of an error attr.
+type ErrorDetails struct {
+	Code    string
+	Message string
+	Type    string // Go type of the error, if the attr holds one.
+	Stack   string // Origin frames, one per line.
+}
+
+// ParseErrorAttr reads the value of an error attr, either a plain error or
+// the ErrorGroup logged for *errors.Error.
+func ParseErrorAttr(v slog.Value) ErrorDetails {
+	var details ErrorDetails
+	var err error
+	switch e := v.Any().(type) {
+	case ErrorGroup:
+		err = e.Err
+	case error:
+		err = e
+	}
+	members := groupMembers(v)
+	if code, ok := members["code"]; ok {
+		details.Code = code.String()
+	}
+	switch {
+	case err != nil:
+		details.Message = err.Error()
+		details.Type = fmt.Sprintf("%T", err)
+	case members != nil:
+		details.Message = members["msg"].String()
+	default:
+		details.Message = v.Resolve().String()
+	}
+	if origin, ok := members["origin"]; ok && origin.Kind() == slog.KindGroup {
+		var frames []string
+		for _, frame := range origin.Group() {
+			frames = append(frames, frame.Value.String())
+		}
+		details.Stack = strings.Join(frames, "\n")
+	}
+	return details
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DurationKey is the key used by Event to record the duration of the unit of work.
+const DurationKey = "duration"
+
+type eventKey struct{}
+
+// Event accumulates the fields of a unit of work and emits them as a single
+// record when it ends. It is safe for concurrent use.
+// Methods on a nil Event are no-ops, so code can enrich the event found in a
+// context without checking whether one was started.
+type Event struct {
+	logger *Logger
+	ctx    context.Context
+	name   string
+	start  time.Time
+
+	mu     sync.Mutex
+	keys   []string
+	fields map[string]any
+	err    error
+	ended  bool
+}
+
+// StartEvent starts an event named name. The event is attached to the context
+// returned by Event.Context, and its record is logged with ctx.
+func (l *Logger) StartEvent(ctx context.Context, name string) *Event {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ev := &Event{
+		logger: l,
+		name:   name,
+		start:  time.Now(),
+		fields: make(map[string]any),
+	}
+	ev.ctx = context.WithValue(ctx, eventKey{}, ev)
+	return ev
+}
+
+// EventFromContext returns the event attached to ctx, or nil.
+func EventFromContext(ctx context.Context) *Event {
+	if ctx == nil {
+		return nil
+	}
+	ev, _ := ctx.Value(eventKey{}).(*Event)
+	return ev
+}
+
+// Context returns a context carrying the event.
+func (e *Event) Context() context.Context {
+	if e == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// Set records value under key, replacing any previous value.
+// Setting an error value also records it as the event error.
+func (e *Event) Set(key string, value any) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ended {
+		return
+	}
+	if err, ok := value.(error); ok && err != nil {
+		e.err = err
+	}
+	e.set(key, value)
+}
+
+// Add increments the numeric field key by n.
+// A field holding a non-integer value is replaced by n.
+func (e *Event) Add(key string, n int64) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ended {
+		return
+	}
+	current, _ := e.fields[key].(int64)
+	e.set(key, current+n)
+}
+
+// SetError records err as the event error. Events with an error end at the
+// error level.
+func (e *Event) SetError(err error) {
+	if e == nil || err == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ended {
+		return
+	}
+	e.err = err
+}
+
+func (e *Event) set(key string, value any) {
+	if _, found := e.fields[key]; !found {
+		e.keys = append(e.keys, key)
+	}
+	e.fields[key] = value
+}
+
+// End logs the event with its duration and accumulated fields. The record is
+// logged at the info level, or at the error level when an error was recorded.
+// Only the first call logs; later calls and updates are ignored.
+func (e *Event) End() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if e.ended {
+		e.mu.Unlock()
+		return
+	}
+	e.ended = true
+	level := slog.LevelInfo
+	args := make([]any, 0, len(e.keys)+2)
+	args = append(args, slog.Duration(DurationKey, time.Since(e.start)))
+	for _, key := range e.keys {
+		args = append(args, slog.Any(key, e.fields[key]))
+	}
+	if e.err != nil {
+		level = slog.LevelError
+		if _, found := e.fields["error"]; !found {
+			args = append(args, slog.Any("error", e.err))
+		}
+	}
+	e.mu.Unlock()
+
+	e.logger.log(e.ctx, level, e.name, args, false)
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// PanicError is the value Panic and PanicContext panic with.
+type PanicError struct {
+	Message string
+	Attrs   []slog.Attr
+	// Err is the first error found in the arguments of the call, if any.
+	Err error
+}
+
+// Error returns the message of the panic.
+func (e *PanicError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error found in the arguments of the call.
+func (e *PanicError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorGroup logs an error as a custom value, usually a group, while keeping
+// the error available to PanicError.
+type ErrorGroup struct {
+	Err   error
+	Value slog.Value
+}
+
+// LogValue implements slog.LogValuer.
+func (g ErrorGroup) LogValue() slog.Value {
+	return g.Value
+}
+
+func newPanicError(msg string, args []any) *PanicError {
+	record := slog.NewRecord(time.Time{}, LevelPanic, msg, 0)
+	record.Add(args...)
+	pe := &PanicError{Message: msg, Attrs: make([]slog.Attr, 0, record.NumAttrs())}
+	record.Attrs(func(attr slog.Attr) bool {
+		pe.Attrs = append(pe.Attrs, attr)
+		if pe.Err == nil {
+			switch v := attr.Value.Any().(type) {
+			case ErrorGroup:
+				pe.Err = v.Err
+			case error:
+				pe.Err = v
+			}
+		}
+		return true
+	})
+	return pe
+}
+
+// WithExitFunc replaces os.Exit as the function called by Fatal and FatalContext.
+func WithExitFunc(exit func(code int)) Option {
+	return func(l *Logger) {
+		l.exitFunc = exit
+	}
+}
+
+// WithExitCode sets the code Fatal and FatalContext exit with. Defaults to 1.
+func WithExitCode(code int) Option {
+	return func(l *Logger) {
+		l.exitCode = code
+	}
+}
+
+// SetExitFunc replaces the function called by Fatal and FatalContext.
+// A nil exit restores os.Exit.
+func (l *Logger) SetExitFunc(exit func(code int)) {
+	l = l.root()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitFunc = exit
+}
+
+// SetExitCode sets the code Fatal and FatalContext exit with.
+func (l *Logger) SetExitCode(code int) {
+	l = l.root()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitCode = code
+}
+
+// OnExit registers a hook run by Fatal and FatalContext before the handler
+// is flushed and the application exits. Hooks run in registration order.
+func (l *Logger) OnExit(hook func()) {
+	if hook == nil {
+		return
+	}
+	l = l.root()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitHooks = append(l.exitHooks, hook)
+}
+
+// exit runs the exit hooks, flushes the handler and calls the exit function.
+func (l *Logger) exit() {
+	l = l.root()
+	l.mu.RLock()
+	hooks := l.exitHooks
+	exit, code := l.exitFunc, l.exitCode
+	l.mu.RUnlock()
+	for _, hook := range hooks {
+		hook()
+	}
+	l.flushBeforeExit()
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(code)
+}
+
+func (l *Logger) flushBeforeExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), FlushTimeout)
+	defer cancel()
+	_ = l.Flush(ctx)
+}
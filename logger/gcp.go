@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Cloud Logging special fields, see
+// https://cloud.google.com/logging/docs/structured-logging#special-payload-fields.
+const (
+	GCPSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	GCPTraceKey          = "logging.googleapis.com/trace"
+	GCPSpanIDKey         = "logging.googleapis.com/spanId"
+	GCPTraceSampledKey   = "logging.googleapis.com/trace_sampled"
+	// HTTPRequestKey is the attr key of request details, see HTTPRequest.
+	HTTPRequestKey = "httpRequest"
+)
+
+// GCPOptions configures a Google Cloud Logging handler.
+type GCPOptions struct {
+	// ProjectID qualifies trace IDs as projects/ID/traces/TRACE_ID.
+	// Defaults to the GOOGLE_CLOUD_PROJECT environment variable; trace IDs
+	// are written bare when neither is set.
+	ProjectID string
+	// Level is the minimum level handled. Defaults to INFO.
+	Level slog.Leveler
+}
+
+// NewGCPHandler returns a handler writing JSON in the Cloud Logging
+// structured layout: severity, message, the source group as
+// logging.googleapis.com/sourceLocation, the trace and span IDs of the span
+// in the record context, and the httpRequest attr at the top level.
+func NewGCPHandler(w io.Writer, opts GCPOptions) slog.Handler {
+	if opts.ProjectID == "" {
+		opts.ProjectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	return newShapeHandler(w, opts.Level, &recordShape{
+		replaceAttr: gcpReplaceAttr,
+		lift:        map[string]func(slog.Value) bool{"source": isGroup, HTTPRequestKey: isGroup},
+		fields: func(ctx context.Context, _ slog.Record, lifted map[string]slog.Value) []slog.Attr {
+			return gcpFields(ctx, opts.ProjectID, lifted)
+		},
+	})
+}
+
+// GCPSeverity maps a level to a Cloud Logging severity.
+func GCPSeverity(level slog.Level) string {
+	switch {
+	case level >= LevelPanic:
+		return "ALERT"
+	case level >= LevelFatal:
+		return "CRITICAL"
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARNING"
+	case level > slog.LevelInfo:
+		return "NOTICE"
+	case level == slog.LevelInfo:
+		return "INFO"
+	}
+	return "DEBUG"
+}
+
+func gcpReplaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return attr
+	}
+	switch attr.Key {
+	case slog.LevelKey:
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			return slog.String("severity", GCPSeverity(level))
+		}
+	case slog.MessageKey:
+		attr.Key = "message"
+	}
+	return attr
+}
+
+func gcpFields(ctx context.Context, projectID string, lifted map[string]slog.Value) []slog.Attr {
+	var fields []slog.Attr
+	if source := groupMembers(lifted["source"]); source != nil {
+		var location []any
+		if file, ok := source["file"]; ok {
+			location = append(location, slog.String("file", file.String()))
+		}
+		if line, ok := source["line"]; ok {
+			// LogEntrySourceLocation.line is an int64, encoded as a JSON string.
+			location = append(location, slog.String("line", line.String()))
+		}
+		if function, ok := source["func"]; ok {
+			location = append(location, slog.String("function", function.String()))
+		}
+		fields = append(fields, slog.Group(GCPSourceLocationKey, location...))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		traceID := sc.TraceID().String()
+		if projectID != "" {
+			traceID = "projects/" + projectID + "/traces/" + traceID
+		}
+		fields = append(fields,
+			slog.String(GCPTraceKey, traceID),
+			slog.String(GCPSpanIDKey, sc.SpanID().String()),
+			slog.Bool(GCPTraceSampledKey, sc.IsSampled()),
+		)
+	}
+	if request, ok := lifted[HTTPRequestKey]; ok {
+		fields = append(fields, slog.Attr{Key: HTTPRequestKey, Value: request})
+	}
+	return fields
+}
+
+// HTTPRequest describes a served request in the Cloud Logging HttpRequest
+// layout. Log it under HTTPRequestKey:
+//
+//	l.Info("served", HTTPRequestKey, logger.NewHTTPRequest(r, status, size, latency))
+type HTTPRequest struct {
+	Method       string
+	URL          string
+	Status       int
+	RequestSize  int64
+	ResponseSize int64
+	UserAgent    string
+	RemoteIP     string
+	Referer      string
+	Latency      time.Duration
+	Protocol     string
+}
+
+// NewHTTPRequest describes r, served with status and a response of size
+// bytes in latency.
+func NewHTTPRequest(r *http.Request, status int, size int64, latency time.Duration) HTTPRequest {
+	return HTTPRequest{
+		Method:       r.Method,
+		URL:          r.URL.String(),
+		Status:       status,
+		RequestSize:  max(r.ContentLength, 0),
+		ResponseSize: size,
+		UserAgent:    r.UserAgent(),
+		RemoteIP:     remoteIP(r.RemoteAddr),
+		Referer:      r.Referer(),
+		Latency:      latency,
+		Protocol:     r.Proto,
+	}
+}
+
+// remoteIP returns the host of a RemoteAddr, without its port.
+func remoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// LogValue implements slog.LogValuer.
+func (r HTTPRequest) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("requestMethod", r.Method),
+		slog.String("requestUrl", r.URL),
+		slog.Int("status", r.Status),
+	}
+	if r.RequestSize > 0 {
+		attrs = append(attrs, slog.String("requestSize", strconv.FormatInt(r.RequestSize, 10)))
+	}
+	if r.ResponseSize > 0 {
+		attrs = append(attrs, slog.String("responseSize", strconv.FormatInt(r.ResponseSize, 10)))
+	}
+	if r.UserAgent != "" {
+		attrs = append(attrs, slog.String("userAgent", r.UserAgent))
+	}
+	if r.RemoteIP != "" {
+		attrs = append(attrs, slog.String("remoteIp", r.RemoteIP))
+	}
+	if r.Referer != "" {
+		attrs = append(attrs, slog.String("referer", r.Referer))
+	}
+	if r.Latency > 0 {
+		attrs = append(attrs, slog.String("latency", strconv.FormatFloat(r.Latency.Seconds(), 'f', -1, 64)+"s"))
+	}
+	if r.Protocol != "" {
+		attrs = append(attrs, slog.String("protocol", r.Protocol))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// SetGCPHandler configures the logger to emit JSON in the Google Cloud
+// Logging structured layout to the provided writer. A nil opts.Level follows
+// the logger level.
+func (l *Logger) SetGCPHandler(w io.Writer, opts GCPOptions) {
+	if opts.Level == nil {
+		opts.Level = l.root().names
+	}
+	l.setBackend(slog.New(NewGCPHandler(w, opts)))
+}
+
+// WithGCPHandler configures a Google Cloud Logging handler that writes to w.
+func WithGCPHandler(w io.Writer, opts GCPOptions) Option {
+	return func(l *Logger) {
+		l.SetGCPHandler(w, opts)
+	}
+}
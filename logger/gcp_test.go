@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeJSONLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record map[string]any
+		if err := dec.Decode(&record); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func testSpanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestGCPHandlerWritesSpecialFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithGCPHandler(&buf, GCPOptions{ProjectID: "acme"}), WithLevel(LevelTrace))
+	l.SetCalldepth(6)
+
+	l.WithGroup("req").InfoContext(testSpanContext(t), "served", "id", 7)
+	records := decodeJSONLines(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	got := records[0]
+	if got["severity"] != "INFO" || got["message"] != "served" || got["time"] == nil {
+		t.Fatalf("record = %v, want severity, message and time", got)
+	}
+	if _, found := got["level"]; found {
+		t.Fatalf("record = %v, want no level field", got)
+	}
+	location, _ := got[GCPSourceLocationKey].(map[string]any)
+	if location["file"] != "gcp_test.go" || location["function"] != "TestGCPHandlerWritesSpecialFields" || location["line"] == "" {
+		t.Fatalf("sourceLocation = %v", got[GCPSourceLocationKey])
+	}
+	if got[GCPTraceKey] != "projects/acme/traces/0af7651916cd43dd8448eb211c80319c" ||
+		got[GCPSpanIDKey] != "b7ad6b7169203331" || got[GCPTraceSampledKey] != true {
+		t.Fatalf("trace fields = %v", got)
+	}
+	if want := map[string]any{"id": float64(7)}; !reflect.DeepEqual(got["req"], want) {
+		t.Fatalf("req = %v, want %v", got["req"], want)
+	}
+}
+
+func TestGCPSeverityMapsCustomLevels(t *testing.T) {
+	tests := map[slog.Level]string{
+		LevelTrace:         "DEBUG",
+		slog.LevelDebug:    "DEBUG",
+		slog.LevelInfo:     "INFO",
+		slog.LevelInfo + 2: "NOTICE",
+		slog.LevelWarn:     "WARNING",
+		slog.LevelError:    "ERROR",
+		LevelFatal:         "CRITICAL",
+		LevelPanic:         "ALERT",
+	}
+	for level, want := range tests {
+		if got := GCPSeverity(level); got != want {
+			t.Errorf("GCPSeverity(%v) = %q, want %q", level, got, want)
+		}
+	}
+}
+
+func TestGCPHandlerLiftsHTTPRequest(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewGCPHandler(&buf, GCPOptions{})
+	r := httptest.NewRequest("GET", "/orders?id=7", nil)
+	r.Header.Set("User-Agent", "curl/8")
+
+	l := slog.New(h).With("service", "api").WithGroup("req")
+	l.Info("served", HTTPRequestKey, NewHTTPRequest(r, 200, 512, 1500*time.Millisecond))
+	got := decodeJSONLines(t, &buf)[0]
+
+	want := map[string]any{
+		"requestMethod": "GET",
+		"requestUrl":    "/orders?id=7",
+		"status":        float64(200),
+		"responseSize":  "512",
+		"userAgent":     "curl/8",
+		"remoteIp":      "192.0.2.1",
+		"latency":       "1.5s",
+		"protocol":      "HTTP/1.1",
+	}
+	if !reflect.DeepEqual(got[HTTPRequestKey], want) {
+		t.Fatalf("httpRequest = %v, want %v", got[HTTPRequestKey], want)
+	}
+	if got["service"] != "api" || got["req"] != nil {
+		t.Fatalf("record = %v, want service attr and no empty req group", got)
+	}
+	if _, found := got[GCPTraceKey]; found {
+		t.Fatalf("record = %v, want no trace without a span", got)
+	}
+}
+
+func TestGCPHandlerKeepsAttrsOfOtherShapes(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewGCPHandler(&buf, GCPOptions{})).Info("served", "source", "cache", HTTPRequestKey, "GET /")
+	got := decodeJSONLines(t, &buf)[0]
+
+	if got["source"] != "cache" || got[HTTPRequestKey] != "GET /" || got[GCPSourceLocationKey] != nil {
+		t.Fatalf("record = %v, want source and httpRequest kept as plain attrs", got)
+	}
+}
+
+func TestSetGCPHandlerKeepsCallerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithGCPHandler(&buf, GCPOptions{Level: slog.LevelWarn}), WithLevel(slog.LevelDebug))
+
+	l.Info("hidden")
+	l.Warn("visible")
+	if records := decodeJSONLines(t, &buf); len(records) != 1 || records[0]["message"] != "visible" {
+		t.Fatalf("records = %v, want only the warning", records)
+	}
+}
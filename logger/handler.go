@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// FlushTimeout bounds the flush performed by Fatal and Panic before they exit
+// or panic.
+const FlushTimeout = 5 * time.Second
+
+// Flusher is implemented by handlers that buffer records.
+// Flush returns once the records handled before the call are written, or ctx is done.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by handlers that hold resources such as queues,
+// files or connections. Close flushes pending records before releasing them.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// FlushHandler flushes h if it implements Flusher.
+func FlushHandler(ctx context.Context, h slog.Handler) error {
+	if f, ok := h.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// CloseHandler closes h if it implements Closer, or flushes it otherwise.
+func CloseHandler(ctx context.Context, h slog.Handler) error {
+	if c, ok := h.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return FlushHandler(ctx, h)
+}
+
+// InsertAttrs returns a copy of attrs with add appended inside the nested
+// groups named by path, reusing the last group of each name. Handlers that
+// keep the attrs and groups of WithAttrs and WithGroup themselves use it to
+// place new attrs.
+func InsertAttrs(attrs []slog.Attr, path []string, add []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, len(attrs), len(attrs)+len(add)+1)
+	copy(out, attrs)
+	if len(path) == 0 {
+		return append(out, add...)
+	}
+	for i := len(out) - 1; i >= 0; i-- {
+		if out[i].Key == path[0] && out[i].Value.Kind() == slog.KindGroup {
+			members := InsertAttrs(out[i].Value.Group(), path[1:], add)
+			out[i] = slog.Attr{Key: path[0], Value: slog.GroupValue(members...)}
+			return out
+		}
+	}
+	members := InsertAttrs(nil, path[1:], add)
+	return append(out, slog.Attr{Key: path[0], Value: slog.GroupValue(members...)})
+}
+
+// levelHandler enforces the Logger level on handlers that were built without it.
+type levelHandler struct {
+	level slog.Leveler
+	next  slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, next: h.next.WithGroup(name)}
+}
+
+func (h *levelHandler) Flush(ctx context.Context) error {
+	return FlushHandler(ctx, h.next)
+}
+
+func (h *levelHandler) Close(ctx context.Context) error {
+	return CloseHandler(ctx, h.next)
+}
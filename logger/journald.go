@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultJournaldSocket is the socket of the journald native protocol.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldOptions configures a JournaldHandler.
+type JournaldOptions struct {
+	// SocketPath defaults to DefaultJournaldSocket.
+	SocketPath string
+	// Identifier is sent as SYSLOG_IDENTIFIER. Defaults to the executable name.
+	Identifier string
+	// Level is the minimum level handled. Defaults to INFO.
+	Level slog.Leveler
+}
+
+// journaldConn is the unconnected socket shared by a JournaldHandler and the handlers
+// derived from it.
+type journaldConn struct {
+	opts   JournaldOptions
+	mu     sync.Mutex
+	conn   *net.UnixConn
+	closed bool
+}
+
+// JournaldHandler sends records to systemd-journald over its native
+// protocol. The message, PRIORITY, SYSLOG_IDENTIFIER and the CODE_FILE,
+// CODE_LINE and CODE_FUNC fields taken from the source group are sent along
+// with every attr as a journal field: keys are upper-cased, groups are
+// joined with underscores and other characters become underscores, so
+// "req.id" is queryable as REQ_ID. Attrs named like the fields above are
+// sent as ATTR_MESSAGE, ATTR_PRIORITY and so on. Entries too large for a datagram are
+// passed through a sealed memfd.
+type JournaldHandler struct {
+	conn   *journaldConn
+	fields []byte // Preformatted fields from WithAttrs.
+	prefix string
+}
+
+// NewJournaldHandler creates a JournaldHandler writing to opts.SocketPath.
+func NewJournaldHandler(opts JournaldOptions) *JournaldHandler {
+	if opts.SocketPath == "" {
+		opts.SocketPath = DefaultJournaldSocket
+	}
+	if opts.Identifier == "" {
+		opts.Identifier = filepath.Base(os.Args[0])
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	return &JournaldHandler{conn: &journaldConn{opts: opts}}
+}
+
+// Enabled reports whether level reaches JournaldOptions.Level.
+func (h *JournaldHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.conn.opts.Level.Level()
+}
+
+// Handle sends the record as one journal entry.
+func (h *JournaldHandler) Handle(_ context.Context, record slog.Record) error {
+	var b bytes.Buffer
+	appendJournalField(&b, "MESSAGE", record.Message)
+	appendJournalField(&b, "PRIORITY", strconv.Itoa(SyslogSeverity(record.Level)))
+	appendJournalField(&b, "SYSLOG_IDENTIFIER", h.conn.opts.Identifier)
+	b.Write(h.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "source" && attr.Value.Kind() == slog.KindGroup {
+			appendJournalSource(&b, attr.Value.Group())
+			return true
+		}
+		for _, flat := range appendFlatAttr(nil, h.prefix, attr) {
+			appendJournalField(&b, journalFieldName(flat.Key), attrString(flat.Value))
+		}
+		return true
+	})
+	return h.conn.write(b.Bytes())
+}
+
+// WithAttrs returns a JournaldHandler sharing the socket of h.
+func (h *JournaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	b := bytes.NewBuffer(append([]byte(nil), h.fields...))
+	for _, attr := range attrs {
+		if h.prefix == "" && attr.Key == "source" && attr.Value.Kind() == slog.KindGroup {
+			appendJournalSource(b, attr.Value.Group())
+			continue
+		}
+		for _, flat := range appendFlatAttr(nil, h.prefix, attr) {
+			appendJournalField(b, journalFieldName(flat.Key), attrString(flat.Value))
+		}
+	}
+	clone.fields = b.Bytes()
+	return &clone
+}
+
+// WithGroup returns a JournaldHandler sharing the socket of h.
+func (h *JournaldHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// Close closes the socket. Records handled after Close return
+// ErrHandlerClosed.
+func (h *JournaldHandler) Close(context.Context) error {
+	c := h.conn
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// appendJournalSource maps the source group to the CODE_* fields and its
+// stack trace to a multi-line STACK_TRACE field.
+func appendJournalSource(b *bytes.Buffer, attrs []slog.Attr) {
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "func":
+			appendJournalField(b, "CODE_FUNC", attr.Value.String())
+		case "file":
+			appendJournalField(b, "CODE_FILE", attr.Value.String())
+		case "line":
+			appendJournalField(b, "CODE_LINE", attr.Value.String())
+		case "stack_trace":
+			var frames []string
+			for _, frame := range attr.Value.Group() {
+				frames = append(frames, frame.Value.String())
+			}
+			appendJournalField(b, "STACK_TRACE", strings.Join(frames, "\n"))
+		}
+	}
+}
+
+// appendJournalField encodes a field as NAME=value, or in the binary form
+// when the value spans several lines.
+func appendJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// reservedJournalFields are the fields written by the handler itself.
+var reservedJournalFields = map[string]bool{
+	"MESSAGE": true, "PRIORITY": true, "SYSLOG_IDENTIFIER": true,
+	"CODE_FUNC": true, "CODE_FILE": true, "CODE_LINE": true, "STACK_TRACE": true,
+}
+
+// journalFieldName returns key as a valid journal field name: upper-case
+// letters, digits and underscores, not starting with an underscore or a
+// digit, at most 64 characters. Names of the fields written by the handler
+// are prefixed with ATTR_ so attrs cannot duplicate them.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "X_" + name
+	}
+	if reservedJournalFields[name] {
+		name = "ATTR_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func (c *journaldConn) write(entry []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrHandlerClosed
+	}
+	if c.conn == nil {
+		conn, err := journaldSocket()
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+	}
+	addr := &net.UnixAddr{Name: c.opts.SocketPath, Net: "unixgram"}
+	_, err := c.conn.WriteToUnix(entry, addr)
+	if isMessageTooLarge(err) {
+		return sendJournalFD(c.conn, addr, entry)
+	}
+	return err
+}
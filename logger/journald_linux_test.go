@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func listenJournald(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, path
+}
+
+// readJournalEntry reads one entry, following a passed memfd if any, and
+// decodes its fields.
+func readJournalEntry(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+	buf := make([]byte, 1<<20)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("ReadMsgUnix() error = %v", err)
+	}
+	data := buf[:n]
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil || len(msgs) != 1 {
+			t.Fatalf("ParseSocketControlMessage() = %v, %v", msgs, err)
+		}
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		if err != nil || len(fds) != 1 {
+			t.Fatalf("ParseUnixRights() = %v, %v", fds, err)
+		}
+		file := os.NewFile(uintptr(fds[0]), "entry")
+		defer file.Close()
+		// The descriptor shares the sender offset; read from the start.
+		if data, err = io.ReadAll(io.NewSectionReader(file, 0, 1<<30)); err != nil {
+			t.Fatalf("read memfd error = %v", err)
+		}
+	}
+
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+		if name, value, ok := bytes.Cut(line, []byte("=")); ok {
+			fields[string(name)] = string(value)
+			data = rest
+			continue
+		}
+		size := binary.LittleEndian.Uint64(rest[:8])
+		fields[string(line)] = string(rest[8 : 8+size])
+		data = rest[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldHandlerSendsFields(t *testing.T) {
+	server, path := listenJournald(t)
+	h := NewJournaldHandler(JournaldOptions{SocketPath: path, Identifier: "billing", Level: LevelTrace})
+	defer h.Close(context.Background())
+	l := NewWithOptions(WithHandler(h), WithLevel(LevelTrace))
+	l.SetCalldepth(6)
+
+	l.With("service", "api").WithGroup("req").Warn("slow", "id", 7, "query", "select 1\nfrom dual", "_pid", 1)
+	fields := readJournalEntry(t, server)
+	want := map[string]string{
+		"MESSAGE":           "slow",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "billing",
+		"SERVICE":           "api",
+		"REQ_ID":            "7",
+		"REQ_QUERY":         "select 1\nfrom dual",
+		"REQ__PID":          "1",
+		"CODE_FUNC":         "TestJournaldHandlerSendsFields",
+		"CODE_FILE":         "journald_linux_test.go",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("field %s = %q, want %q (fields %v)", name, fields[name], value, fields)
+		}
+	}
+	if fields["CODE_LINE"] == "" {
+		t.Errorf("CODE_LINE missing from %v", fields)
+	}
+
+	l.Print("trace")
+	if got := readJournalEntry(t, server)["PRIORITY"]; got != "7" {
+		t.Errorf("TRACE PRIORITY = %q, want 7", got)
+	}
+}
+
+func TestJournaldHandlerPrefixesReservedFieldNames(t *testing.T) {
+	server, path := listenJournald(t)
+	h := NewJournaldHandler(JournaldOptions{SocketPath: path, Identifier: "billing"})
+	defer h.Close(context.Background())
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "charged", 0)
+	record.AddAttrs(slog.String("message", "user"), slog.Int("priority", 1), slog.String("syslog_identifier", "spoofed"))
+	if err := h.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	fields := readJournalEntry(t, server)
+	want := map[string]string{
+		"MESSAGE":                "charged",
+		"PRIORITY":               "6",
+		"SYSLOG_IDENTIFIER":      "billing",
+		"ATTR_MESSAGE":           "user",
+		"ATTR_PRIORITY":          "1",
+		"ATTR_SYSLOG_IDENTIFIER": "spoofed",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("field %s = %q, want %q (fields %v)", name, fields[name], value, fields)
+		}
+	}
+}
+
+func TestJournaldHandlerSendsStackTrace(t *testing.T) {
+	server, path := listenJournald(t)
+	h := NewJournaldHandler(JournaldOptions{SocketPath: path, Level: slog.LevelDebug})
+	defer h.Close(context.Background())
+	l := NewWithOptions(WithHandler(h), WithLevel(slog.LevelDebug), WithDebugStackTrace(true))
+	l.SetCalldepth(5)
+
+	l.Debug("stopping")
+	fields := readJournalEntry(t, server)
+	frames := strings.Split(fields["STACK_TRACE"], "\n")
+	if len(frames) < 2 || !strings.HasPrefix(frames[0], "journald_linux_test.go:") {
+		t.Fatalf("STACK_TRACE = %q, want one frame per line from the caller", fields["STACK_TRACE"])
+	}
+}
+
+func TestJournaldHandlerPassesLargeEntriesThroughMemfd(t *testing.T) {
+	server, path := listenJournald(t)
+	h := NewJournaldHandler(JournaldOptions{SocketPath: path})
+	defer h.Close(context.Background())
+
+	payload := strings.Repeat("x", 512<<10)
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "large", 0)
+	record.AddAttrs(slog.String("payload", payload))
+	if err := h.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	fields := readJournalEntry(t, server)
+	if fields["MESSAGE"] != "large" || fields["PAYLOAD"] != payload {
+		t.Fatalf("entry = MESSAGE %q with %d-byte PAYLOAD, want large with %d bytes",
+			fields["MESSAGE"], len(fields["PAYLOAD"]), len(payload))
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package logger
+
+import (
+	"errors"
+	"net"
+)
+
+var errJournaldUnsupported = errors.New("logger: journald requires linux")
+
+func journaldSocket() (*net.UnixConn, error) {
+	return nil, errJournaldUnsupported
+}
+
+func isMessageTooLarge(error) bool {
+	return false
+}
+
+func sendJournalFD(*net.UnixConn, *net.UnixAddr, []byte) error {
+	return errJournaldUnsupported
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LogfmtHandler writes records as logfmt lines:
+//
+//	time=2024-01-02T15:04:05.000Z level=INFO msg="request served" req.id=7 source.file=main.go
+//
+// Groups are flattened into dotted keys. Values are quoted when empty or
+// when they contain spaces, '=', quotes, backslashes, control or
+// non-printable characters; inside quotes, quotes and backslashes are
+// escaped and control characters use Go escapes such as \n. AddSource, Level
+// and ReplaceAttr in the options behave as in slog.TextHandler.
+type LogfmtHandler struct {
+	opts   slog.HandlerOptions
+	mu     *sync.Mutex
+	w      io.Writer
+	attrs  []byte // Preformatted attrs from WithAttrs.
+	groups []string
+}
+
+// NewLogfmtHandler creates a LogfmtHandler writing to w.
+// A nil opts is the same as the zero slog.HandlerOptions.
+func NewLogfmtHandler(w io.Writer, opts *slog.HandlerOptions) *LogfmtHandler {
+	h := &LogfmtHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled reports whether level reaches the handler level. Defaults to INFO.
+func (h *LogfmtHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minimum := slog.LevelInfo
+	if h.opts.Level != nil {
+		minimum = h.opts.Level.Level()
+	}
+	return level >= minimum
+}
+
+// Handle writes the record as one line.
+func (h *LogfmtHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	if !record.Time.IsZero() {
+		h.appendAttr(&b, nil, "", slog.Time(slog.TimeKey, record.Time))
+	}
+	h.appendAttr(&b, nil, "", slog.Any(slog.LevelKey, record.Level))
+	h.appendAttr(&b, nil, "", slog.String(slog.MessageKey, record.Message))
+	if h.opts.AddSource && record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		h.appendAttr(&b, nil, "", slog.Any(slog.SourceKey, &slog.Source{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		}))
+	}
+	b.Write(h.attrs)
+	prefix := groupPrefix(h.groups)
+	record.Attrs(func(attr slog.Attr) bool {
+		h.appendAttr(&b, h.groups, prefix, attr)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, strings.TrimPrefix(b.String(), " "))
+	return err
+}
+
+// WithAttrs returns a LogfmtHandler sharing the writer of h.
+func (h *LogfmtHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	var b strings.Builder
+	b.Write(h.attrs)
+	prefix := groupPrefix(h.groups)
+	for _, attr := range attrs {
+		h.appendAttr(&b, h.groups, prefix, attr)
+	}
+	clone.attrs = []byte(b.String())
+	return &clone
+}
+
+// WithGroup returns a LogfmtHandler sharing the writer of h.
+func (h *LogfmtHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+// appendAttr writes attr as " key=value", passing it through ReplaceAttr
+// and flattening groups.
+func (h *LogfmtHandler) appendAttr(b *strings.Builder, groups []string, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+			prefix += attr.Key + "."
+		}
+		for _, member := range members {
+			h.appendAttr(b, groups, prefix, member)
+		}
+		return
+	}
+	if h.opts.ReplaceAttr != nil {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+		if attr.Value.Kind() == slog.KindGroup {
+			h.appendAttr(b, groups, prefix, attr)
+			return
+		}
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if source, ok := attr.Value.Any().(*slog.Source); ok {
+		h.appendAttr(b, groups, prefix, slog.Group(attr.Key,
+			slog.String("function", source.Function),
+			slog.String("file", source.File),
+			slog.Int("line", source.Line),
+		))
+		return
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(logfmtKey(prefix + attr.Key))
+	b.WriteByte('=')
+	var value string
+	if attr.Value.Kind() == slog.KindTime && attr.Key == slog.TimeKey && len(groups) == 0 {
+		value = attr.Value.Time().Format("2006-01-02T15:04:05.000Z07:00")
+	} else {
+		value = attrString(attr.Value)
+	}
+	b.WriteString(logfmtValue(value))
+}
+
+// logfmtKey replaces the characters logfmt does not allow in keys with
+// underscores.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
+
+// SetLogfmtHandler configures the logger to emit logfmt logs to the provided writer.
+func (l *Logger) SetLogfmtHandler(w io.Writer) {
+	l.setBackend(slog.New(NewLogfmtHandler(w, l.handlerOptions())))
+}
+
+// WithLogfmtHandler configures a logfmt handler that writes to w.
+func WithLogfmtHandler(w io.Writer) Option {
+	return func(l *Logger) {
+		l.SetLogfmtHandler(w)
+	}
+}
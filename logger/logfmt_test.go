@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogfmtHandlerFlattensGroupsAndQuotes(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewLogfmtHandler(&buf, nil)
+	logger := slog.New(h).With("service", "api").WithGroup("req")
+
+	record := slog.NewRecord(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), slog.LevelWarn, "request served", 0)
+	record.AddAttrs(
+		slog.Int("id", 7),
+		slog.String("path", "/a b"),
+		slog.String("quote", `say "hi"`),
+		slog.String("lines", "one\ntwo"),
+		slog.String("empty", ""),
+		slog.String("unicode", "héllo"),
+		slog.String("control", "bell\a"),
+		slog.Group("user", slog.String("name", "ana"), slog.Group("empty")),
+		slog.Any("err", errors.New("boom")),
+		slog.String("bad key", "v"),
+	)
+	if err := logger.Handler().Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	want := `time=2024-01-02T15:04:05.000Z level=WARN msg="request served" service=api req.id=7 req.path="/a b" ` +
+		`req.quote="say \"hi\"" req.lines="one\ntwo" req.empty="" req.unicode=héllo req.control="bell\a" ` +
+		`req.user.name=ana req.err=boom req.bad_key=v` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLogfmtHandlerAppliesReplaceAttrLevelNames(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithLogfmtHandler(&buf), WithLevel(LevelTrace))
+	l.SetCalldepth(6)
+
+	l.Print("tracing", "k", "v")
+	re := regexp.MustCompile(`^time=\S+ level=TRACE msg=tracing k=v source.func=TestLogfmtHandlerAppliesReplaceAttrLevelNames source.file=logfmt_test.go source.line=\d+\n$`)
+	if !re.MatchString(buf.String()) {
+		t.Fatalf("output = %q, want match %s", buf.String(), re)
+	}
+}
+
+func TestLogfmtHandlerPassesGroupsToReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	var seen []string
+	h := NewLogfmtHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			seen = append(seen, strings.Join(append(groups, attr.Key), "."))
+			if attr.Key == slog.TimeKey || attr.Key == "secret" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	slog.New(h).WithGroup("req").Info("msg", "secret", "x", slog.Group("user", "id", 1))
+
+	if got, want := buf.String(), "level=INFO msg=msg req.user.id=1\n"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(seen, ","), "time,level,msg,req.secret,req.user.id"; got != want {
+		t.Fatalf("ReplaceAttr keys = %s, want %s", got, want)
+	}
+}
@@ -4,6 +4,7 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
@@ -19,6 +20,9 @@ type Priority int8
 type Option func(*Logger)
 
 // Logger manages logging operations with various log levels and modes.
+// The child loggers returned by With, WithGroup and Named only add their
+// attributes, group or name: the handler, level, source, calldepth and exit
+// settings stay on the root logger and are shared by all its children.
 type Logger struct {
 	calldepth  int // Number of stack frames to ascend when generating log entries.
 	level      *slog.LevelVar
@@ -26,13 +30,30 @@ type Logger struct {
 	debugStack bool
 	mu         sync.RWMutex
 	logger     *slog.Logger
+	parent     *Logger      // Root logger that owns the backend and settings of a child logger.
+	scope      []scopeFunc  // Attributes and groups applied by a child on top of the root backend.
+	base       *slog.Logger // Root backend the cached child backend was derived from.
+	name       string       // Name of a named logger, see Named.
+	grouped    bool         // Whether scope starts a group, see WithGroup.
+	names      *levelRegistry
+	exitFunc   func(code int)
+	exitCode   int
+	exitHooks  []func()
+	closers    []io.Closer // Writers owned by the logger, closed by Close.
 }
 
+// scopeFunc derives a backend carrying the attributes or group of a child logger.
+type scopeFunc func(*slog.Logger) *slog.Logger
+
 type loggerConfig struct {
 	calldepth  int
 	addSource  bool
 	debugStack bool
 	logger     *slog.Logger
+	name       string
+	names      *levelRegistry
+	base       *slog.Logger // Root backend of a grouped child, see scoped.
+	scope      []scopeFunc
 }
 
 // WithLevel sets the initial logging level.
@@ -70,6 +91,14 @@ func WithTextHandler(w io.Writer) Option {
 	}
 }
 
+// WithHandler configures h as the backend of the logger.
+// See Logger.SetHandler for details.
+func WithHandler(h slog.Handler) Option {
+	return func(l *Logger) {
+		l.SetHandler(h)
+	}
+}
+
 // New creates and initializes a new Logger instance.
 // calldepth: Number of stack frames to ascend for log entries.
 // pc: Deprecated and ignored. Kept for backward compatibility.
@@ -84,6 +113,8 @@ func New(calldepth int, _ []uintptr, levels ...*slog.LevelVar) *Logger {
 		calldepth: calldepth,
 		level:     level,
 		addSource: true,
+		names:     newLevelRegistry(level),
+		exitCode:  1,
 	}
 	l.SetJSONHandler(os.Stderr)
 	return l
@@ -100,7 +131,23 @@ func NewWithOptions(opts ...Option) *Logger {
 	return l
 }
 
+func (l *Logger) root() *Logger {
+	if l.parent != nil {
+		return l.parent
+	}
+	return l
+}
+
 func (l *Logger) snapshot() loggerConfig {
+	if l.parent != nil {
+		cfg := l.parent.snapshot()
+		if l.grouped {
+			cfg.base, cfg.scope = cfg.logger, l.scope
+		}
+		cfg.logger = l.derive(cfg.logger)
+		cfg.name = l.name
+		return cfg
+	}
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	return loggerConfig{
@@ -108,15 +155,95 @@ func (l *Logger) snapshot() loggerConfig {
 		addSource:  l.addSource,
 		debugStack: l.debugStack,
 		logger:     l.logger,
+		names:      l.names,
+	}
+}
+
+// derive returns the child backend for base, rebuilding it when the root
+// backend was swapped since the last call.
+func (l *Logger) derive(base *slog.Logger) *slog.Logger {
+	l.mu.RLock()
+	next, cached := l.logger, l.base
+	l.mu.RUnlock()
+	if base == cached {
+		return next
+	}
+	next = base
+	if next != nil {
+		for _, fn := range l.scope {
+			next = fn(next)
+		}
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base = base
+	l.logger = next
+	return next
 }
 
 func (l *Logger) setBackend(next *slog.Logger) {
+	l = l.root()
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.logger = next
 }
 
+func (l *Logger) child(fn scopeFunc) *Logger {
+	root := l.root()
+	scope := make([]scopeFunc, 0, len(l.scope)+1)
+	scope = append(scope, l.scope...)
+	if fn != nil {
+		scope = append(scope, fn)
+	}
+	return &Logger{
+		level:   root.level,
+		parent:  root,
+		scope:   scope,
+		name:    l.name,
+		grouped: l.grouped,
+	}
+}
+
+// With returns a child logger that includes the given attributes in each record.
+// The child is not independent of l: it shares the handler, level, source,
+// calldepth, debug stack and exit settings of the root logger, so setters
+// such as SetLevel or SetHandler called on the child change them for the
+// root and all its children.
+func (l *Logger) With(args ...any) *Logger {
+	if len(args) == 0 {
+		return l
+	}
+	return l.child(func(next *slog.Logger) *slog.Logger {
+		return next.With(args...)
+	})
+}
+
+// WithGroup returns a child logger that starts a group. The keys of all
+// attributes added to the child are qualified by the given name. Like With,
+// the child shares the settings of the root logger.
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	child := l.child(func(next *slog.Logger) *slog.Logger {
+		return next.WithGroup(name)
+	})
+	child.grouped = true
+	return child
+}
+
+// enabled reports whether level is enabled for the named logger. DEBUG and
+// TRACE records are checked against the rules of their call site first.
+func (c loggerConfig) enabled(level slog.Level) bool {
+	if level < slog.LevelInfo {
+		// enabled runs one frame closer to the call site than sourceAttr.
+		if siteLevel, ok := c.names.sites.lookup(c.calldepth); ok {
+			return level >= siteLevel
+		}
+	}
+	return level >= c.names.resolve(c.name)
+}
+
 func (c loggerConfig) sourceAttr(withStack bool) slog.Attr {
 	if !c.addSource {
 		return slog.Attr{}
@@ -141,29 +268,79 @@ func (l *Logger) log(ctx context.Context, level slog.Level, msg string, args []a
 }
 
 func logWithConfig(ctx context.Context, level slog.Level, msg string, args []any, cfg loggerConfig, withStack bool) {
-	if cfg.logger == nil || !cfg.logger.Enabled(ctx, level) {
+	if cfg.logger == nil || !cfg.enabled(level) || !cfg.logger.Enabled(ctx, level) {
+		return
+	}
+	// The name, context attributes and source describe the record rather than
+	// the child logger, so they stay out of the groups started by WithGroup.
+	var top []any
+	if cfg.name != "" {
+		top = append(top, slog.String(NameKey, cfg.name))
+	}
+	top = appendContextAttrs(ctx, top)
+	top = cfg.appendSource(top, withStack)
+	if cfg.base != nil && len(top) > 0 {
+		cfg.scoped(top).Log(ctx, level, msg, args...)
 		return
 	}
-	args = cfg.appendSource(args, withStack)
+	args = append(args[:len(args):len(args)], top...)
 	cfg.logger.Log(ctx, level, msg, args...)
 }
 
-// SetJSONHandler configures the logger to emit JSON logs to the provided writer.
-func (l *Logger) SetJSONHandler(w io.Writer) {
-	opts := &slog.HandlerOptions{
-		Level:       l.level,
+// scoped returns the backend of a grouped child rebuilt on top of the root
+// backend with attrs, so that attrs are logged before its groups start.
+func (c loggerConfig) scoped(attrs []any) *slog.Logger {
+	next := c.base.With(attrs...)
+	for _, fn := range c.scope {
+		next = fn(next)
+	}
+	return next
+}
+
+// HandlerOptions returns the options used by the built-in handlers, bound to
+// the logger level and the custom level names. Use it to build handlers that
+// render TRACE, FATAL and PANIC like the JSON and text handlers do. Handlers
+// built with it drop records below the logger level, including those of
+// named loggers and call sites enabled below it.
+func (l *Logger) HandlerOptions() *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level:       l.root().level,
 		ReplaceAttr: ReplaceAttr,
 	}
-	l.setBackend(slog.New(slog.NewJSONHandler(w, opts)))
+}
+
+// handlerOptions returns HandlerOptions bound to the lowest level of the
+// logger, its named loggers and its call sites, for the handlers the logger
+// builds itself and filters with its own levels.
+func (l *Logger) handlerOptions() *slog.HandlerOptions {
+	opts := l.HandlerOptions()
+	opts.Level = l.root().names
+	return opts
+}
+
+// SetJSONHandler configures the logger to emit JSON logs to the provided writer.
+func (l *Logger) SetJSONHandler(w io.Writer) {
+	l.setBackend(slog.New(slog.NewJSONHandler(w, l.handlerOptions())))
 }
 
 // SetTextHandler configures the logger to emit text logs to the provided writer.
 func (l *Logger) SetTextHandler(w io.Writer) {
-	opts := &slog.HandlerOptions{
-		Level:       l.level,
-		ReplaceAttr: ReplaceAttr,
+	l.setBackend(slog.New(slog.NewTextHandler(w, l.handlerOptions())))
+}
+
+// SetHandler configures h as the backend of the logger.
+// Records below the logger level are dropped before reaching h, and source
+// metadata is attached by the logger as usual. A slog.JSONHandler,
+// slog.TextHandler or LogfmtHandler is copied to render TRACE, FATAL and
+// PANIC; other handlers write the level text themselves, so build them with
+// HandlerOptions, or with ReplaceAttr, to keep the custom names. A nil
+// handler disables output.
+func (l *Logger) SetHandler(h slog.Handler) {
+	if h == nil {
+		l.setBackend(nil)
+		return
 	}
-	l.setBackend(slog.New(slog.NewTextHandler(w, opts)))
+	l.setBackend(slog.New(&levelHandler{level: l.root().names, next: withLevelNames(h)}))
 }
 
 // Debug logs a debug-level message with optional arguments.
@@ -198,28 +375,61 @@ func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
 	l.log(ctx, slog.LevelError, msg, args, false)
 }
 
-// Panic logs a panic-level message, then panics with the message.
+// Panic logs a panic-level message, flushes the handler, then panics with a
+// *PanicError carrying the message and arguments.
 func (l *Logger) Panic(msg string, args ...any) {
 	l.log(context.Background(), LevelPanic, msg, args, false)
-	panic(msg)
+	l.flushBeforeExit()
+	panic(newPanicError(msg, args))
 }
 
-// PanicContext logs a panic-level message with context, then panics with the message.
+// PanicContext logs a panic-level message with context, flushes the handler,
+// then panics with a *PanicError carrying the message and arguments.
 func (l *Logger) PanicContext(ctx context.Context, msg string, args ...any) {
 	l.log(ctx, LevelPanic, msg, args, false)
-	panic(msg)
+	l.flushBeforeExit()
+	panic(newPanicError(msg, args))
 }
 
-// Fatal logs a fatal-level message, then exits the application.
+// Fatal logs a fatal-level message, runs the exit hooks, flushes the handler,
+// then exits the application. See WithExitFunc and OnExit.
 func (l *Logger) Fatal(msg string, args ...any) {
 	l.log(context.Background(), LevelFatal, msg, args, false)
-	os.Exit(1)
+	l.exit()
 }
 
-// FatalContext logs a fatal-level message with context, then exits the application.
+// FatalContext logs a fatal-level message with context, runs the exit hooks,
+// flushes the handler, then exits the application.
 func (l *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
 	l.log(ctx, LevelFatal, msg, args, false)
-	os.Exit(1)
+	l.exit()
+}
+
+// Flush flushes the handler of the logger if it buffers records, see Flusher.
+func (l *Logger) Flush(ctx context.Context) error {
+	backend := l.root().snapshot().logger
+	if backend == nil {
+		return nil
+	}
+	return FlushHandler(ctx, backend.Handler())
+}
+
+// Close flushes the handler of the logger and releases its resources, see Closer.
+// Records logged after Close may be rejected by the handler.
+func (l *Logger) Close(ctx context.Context) error {
+	l = l.root()
+	var errs []error
+	if backend := l.snapshot().logger; backend != nil {
+		errs = append(errs, CloseHandler(ctx, backend.Handler()))
+	}
+	l.mu.Lock()
+	closers := l.closers
+	l.closers = nil
+	l.mu.Unlock()
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
 }
 
 // Print logs a trace-level message with optional arguments.
@@ -249,6 +459,7 @@ func (l *Logger) StackTrace() slog.Attr {
 
 // SetCalldepth configures the number of stack frames to ascend for logging.
 func (l *Logger) SetCalldepth(calldepth int) {
+	l = l.root()
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.calldepth = calldepth
@@ -256,6 +467,7 @@ func (l *Logger) SetCalldepth(calldepth int) {
 
 // SetSource controls whether source metadata is attached to log records.
 func (l *Logger) SetSource(enabled bool) {
+	l = l.root()
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.addSource = enabled
@@ -263,6 +475,7 @@ func (l *Logger) SetSource(enabled bool) {
 
 // SetDebugStackTrace controls whether debug logs include a stack trace.
 func (l *Logger) SetDebugStackTrace(enabled bool) {
+	l = l.root()
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.debugStack = enabled
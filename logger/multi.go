@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Destination pairs a handler with the minimum level it receives.
+// A nil Level lets every record enabled by the handler through.
+type Destination struct {
+	Handler slog.Handler
+	Level   slog.Leveler
+}
+
+func (d Destination) enabled(ctx context.Context, level slog.Level) bool {
+	if d.Level != nil && level < d.Level.Level() {
+		return false
+	}
+	return d.Handler.Enabled(ctx, level)
+}
+
+// MultiHandler dispatches each record to several destinations.
+// A failing destination does not prevent the others from receiving the record;
+// Handle returns the errors of all failing destinations joined together.
+type MultiHandler struct {
+	destinations []Destination
+}
+
+// NewMultiHandler creates a MultiHandler for the given destinations.
+// Destinations without a handler are ignored.
+func NewMultiHandler(destinations ...Destination) *MultiHandler {
+	h := &MultiHandler{destinations: make([]Destination, 0, len(destinations))}
+	for _, d := range destinations {
+		if d.Handler != nil {
+			h.destinations = append(h.destinations, d)
+		}
+	}
+	return h
+}
+
+// WithMultiHandler configures a MultiHandler over the given destinations.
+func WithMultiHandler(destinations ...Destination) Option {
+	return func(l *Logger) {
+		l.SetHandler(NewMultiHandler(destinations...))
+	}
+}
+
+// Enabled reports whether any destination accepts records at the given level.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, d := range h.destinations {
+		if d.enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle sends a copy of the record to every destination that accepts its level.
+func (h *MultiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, d := range h.destinations {
+		if !d.enabled(ctx, record.Level) {
+			continue
+		}
+		if err := d.Handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a MultiHandler whose destinations include attrs.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(func(next slog.Handler) slog.Handler {
+		return next.WithAttrs(attrs)
+	})
+}
+
+// WithGroup returns a MultiHandler whose destinations start the group name.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	return h.derive(func(next slog.Handler) slog.Handler {
+		return next.WithGroup(name)
+	})
+}
+
+// Flush flushes every destination.
+func (h *MultiHandler) Flush(ctx context.Context) error {
+	var errs []error
+	for _, d := range h.destinations {
+		if err := FlushHandler(ctx, d.Handler); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every destination.
+func (h *MultiHandler) Close(ctx context.Context) error {
+	var errs []error
+	for _, d := range h.destinations {
+		if err := CloseHandler(ctx, d.Handler); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *MultiHandler) derive(fn func(slog.Handler) slog.Handler) *MultiHandler {
+	next := &MultiHandler{destinations: make([]Destination, len(h.destinations))}
+	for i, d := range h.destinations {
+		next.destinations[i] = Destination{Handler: fn(d.Handler), Level: d.Level}
+	}
+	return next
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error {
+	return h.err
+}
+
+func TestMultiHandlerAppliesPerDestinationLevels(t *testing.T) {
+	var text, json, alerts bytes.Buffer
+	opts := &slog.HandlerOptions{Level: LevelTrace, ReplaceAttr: ReplaceAttr}
+	l := NewWithOptions(
+		WithLevel(slog.LevelDebug),
+		WithMultiHandler(
+			Destination{Handler: slog.NewTextHandler(&text, opts), Level: slog.LevelInfo},
+			Destination{Handler: slog.NewJSONHandler(&json, opts), Level: slog.LevelDebug},
+			Destination{Handler: slog.NewJSONHandler(&alerts, opts), Level: slog.LevelError},
+		),
+	)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Error("error")
+
+	if got := strings.Count(text.String(), "\n"); got != 2 {
+		t.Fatalf("text lines = %d, want 2; output = %q", got, text.String())
+	}
+	if got := strings.Count(json.String(), "\n"); got != 3 {
+		t.Fatalf("json lines = %d, want 3; output = %q", got, json.String())
+	}
+	if got := alerts.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, "\"msg\":\"error\"") {
+		t.Fatalf("expected only the error record in alerts, got %q", got)
+	}
+}
+
+func TestMultiHandlerPropagatesAttrsAndGroups(t *testing.T) {
+	var first, second bytes.Buffer
+	l := NewWithOptions(
+		WithSource(false),
+		WithMultiHandler(
+			Destination{Handler: slog.NewJSONHandler(&first, nil)},
+			Destination{Handler: slog.NewJSONHandler(&second, nil)},
+		),
+	)
+
+	l.With("component", "db").WithGroup("query").Info("hello", "table", "users")
+
+	for _, output := range []string{first.String(), second.String()} {
+		if !strings.Contains(output, "\"component\":\"db\",\"query\":{\"table\":\"users\"}") {
+			t.Fatalf("expected attributes and group in output, got %q", output)
+		}
+	}
+}
+
+func TestMultiHandlerAggregatesErrors(t *testing.T) {
+	var buf bytes.Buffer
+	errA := errors.New("sink a")
+	errB := errors.New("sink b")
+	h := NewMultiHandler(
+		Destination{Handler: failingHandler{Handler: slog.NewJSONHandler(&buf, nil), err: errA}},
+		Destination{Handler: slog.NewJSONHandler(&buf, nil)},
+		Destination{Handler: failingHandler{Handler: slog.NewJSONHandler(&buf, nil), err: errB}},
+	)
+
+	err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("Handle() error = %v, want both sink errors", err)
+	}
+	if !strings.Contains(buf.String(), "\"msg\":\"hello\"") {
+		t.Fatalf("expected healthy sink to receive the record, got %q", buf.String())
+	}
+}
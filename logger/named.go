@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+)
+
+// NameKey is the key used to log the name of a named logger.
+const NameKey = "logger"
+
+// levelRegistry holds the levels configured for named loggers. Levels are
+// resolved hierarchically: "db.pool" falls back to "db", then to the root level.
+type levelRegistry struct {
+	root   *slog.LevelVar
+	mu     sync.RWMutex
+	levels map[string]slog.Level
+	lowest slog.LevelVar // Lowest named level.
+	sites  *callSites
+}
+
+func newLevelRegistry(root *slog.LevelVar) *levelRegistry {
+	r := &levelRegistry{
+		root:   root,
+		levels: make(map[string]slog.Level),
+		sites:  newCallSites(),
+	}
+	r.updateMin()
+	return r
+}
+
+func (r *levelRegistry) set(name string, level slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.levels[name] = level
+	r.updateMin()
+}
+
+func (r *levelRegistry) clear(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.levels, name)
+	r.updateMin()
+}
+
+// updateMin must be called with r.mu held.
+func (r *levelRegistry) updateMin() {
+	lowest := slog.Level(math.MaxInt)
+	for _, level := range r.levels {
+		lowest = min(lowest, level)
+	}
+	r.lowest.Set(lowest)
+}
+
+// resolve returns the effective level of the named logger.
+func (r *levelRegistry) resolve(name string) slog.Level {
+	if name != "" {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if len(r.levels) > 0 {
+			for n := name; ; {
+				if level, found := r.levels[n]; found {
+					return level
+				}
+				i := strings.LastIndexByte(n, '.')
+				if i < 0 {
+					break
+				}
+				n = n[:i]
+			}
+		}
+	}
+	return r.root.Level()
+}
+
+// Level implements slog.Leveler for handlers shared by every named logger,
+// returning the lowest level any of them or any call site may log at.
+func (r *levelRegistry) Level() slog.Level {
+	return min(r.root.Level(), r.lowest.Level(), r.sites.lowest.Level())
+}
+
+// Named returns a child logger named name whose level can be configured with
+// SetNamedLevel. Names are dot-separated; a named logger without its own level
+// uses the level of its closest configured ancestor, or the logger level.
+// Calling Named on a named logger appends name to the current name.
+// The name is logged under NameKey.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	child := l.child(nil)
+	child.name = name
+	return child
+}
+
+// Name returns the name of the logger, or an empty string for unnamed loggers.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetNamedLevel sets the level of the named logger and its descendants.
+func (l *Logger) SetNamedLevel(name string, level slog.Level) {
+	l.root().names.set(name, level)
+}
+
+// ClearNamedLevel removes the level configured for name, so it falls back to
+// its closest configured ancestor.
+func (l *Logger) ClearNamedLevel(name string) {
+	l.root().names.clear(name)
+}
+
+// NamedLevel returns the effective level of the named logger.
+func (l *Logger) NamedLevel(name string) slog.Level {
+	return l.root().names.resolve(name)
+}
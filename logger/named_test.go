@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNamedLevelsResolveHierarchically(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithJSONHandler(&buf), WithLevel(slog.LevelInfo), WithSource(false))
+	pool := l.Named("db").Named("pool")
+	http := l.Named("http")
+
+	l.SetNamedLevel("db", slog.LevelDebug)
+	pool.Debug("pool debug")
+	http.Debug("http debug")
+
+	output := buf.String()
+	if !strings.Contains(output, "\"msg\":\"pool debug\",\"logger\":\"db.pool\"") {
+		t.Fatalf("expected db.pool debug record with logger name, got %q", output)
+	}
+	if strings.Contains(output, "http debug") {
+		t.Fatalf("expected http debug record to be filtered, got %q", output)
+	}
+
+	buf.Reset()
+	l.SetNamedLevel("db.pool", slog.LevelError)
+	pool.Warn("pool warn")
+	l.Named("db").Warn("db warn")
+	if output := buf.String(); strings.Contains(output, "pool warn") || !strings.Contains(output, "db warn") {
+		t.Fatalf("expected only db warn record, got %q", output)
+	}
+
+	buf.Reset()
+	l.ClearNamedLevel("db")
+	l.ClearNamedLevel("db.pool")
+	pool.Debug("hidden")
+	if got := buf.String(); got != "" {
+		t.Fatalf("expected root level after clearing named levels, got %q", got)
+	}
+	if got := l.NamedLevel("db.pool"); got != slog.LevelInfo {
+		t.Fatalf("NamedLevel() = %v, want %v", got, slog.LevelInfo)
+	}
+}
+
+func TestHandlerOptionsUseTheLoggerLevel(t *testing.T) {
+	l := NewWithOptions(WithLevel(slog.LevelInfo))
+	l.SetNamedLevel("db", LevelTrace)
+
+	if got := l.HandlerOptions().Level.Level(); got != slog.LevelInfo {
+		t.Fatalf("HandlerOptions().Level = %v, want %v", got, slog.LevelInfo)
+	}
+	if got := l.Named("db").HandlerOptions().Level.Level(); got != slog.LevelInfo {
+		t.Fatalf("named HandlerOptions().Level = %v, want %v", got, slog.LevelInfo)
+	}
+}
+
+func TestNameContextAttrsAndSourceStayOutOfGroups(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(WithJSONHandler(&buf), WithLevel(slog.LevelInfo))
+	ctx := AddAttrs(context.Background(), slog.String("request_id", "r1"))
+
+	l.Named("db").With("service", "api").WithGroup("req").InfoContext(ctx, "served", "id", 7)
+
+	got := decodeJSONLines(t, &buf)[0]
+	if got["logger"] != "db" || got["request_id"] != "r1" || got["source"] == nil || got["service"] != "api" {
+		t.Fatalf("record = %v, want logger, context attrs, source and service at the top level", got)
+	}
+	if req := got["req"]; !reflect.DeepEqual(req, map[string]any{"id": float64(7)}) {
+		t.Fatalf("req = %#v, want only the record attrs", req)
+	}
+}
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// ReopenFile is an io.Writer writing to a file that can be reopened at the
+// same path, as expected by logrotate's create and postrotate pattern:
+// after the file is renamed, ReopenFile creates a new one on Reopen or when
+// the process receives one of the signals passed to NotifyReopen.
+//
+// Writes are serialized with reopening, so every write goes entirely to
+// either the old or the new file.
+type ReopenFile struct {
+	path string
+	mode os.FileMode
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+
+	sigOnce sync.Once
+	sigCh   chan os.Signal
+	done    chan struct{}
+}
+
+// NewReopenFile opens path for appending, creating it and its directory if needed.
+func NewReopenFile(path string) (*ReopenFile, error) {
+	f := &ReopenFile{
+		path: path,
+		mode: 0o644,
+		done: make(chan struct{}),
+	}
+	file, err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	f.file = file
+	return f, nil
+}
+
+// Write appends p to the file.
+func (f *ReopenFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	return f.file.Write(p)
+}
+
+// Reopen opens the path again and closes the previous file.
+// If the path cannot be opened, writes keep going to the previous file.
+func (f *ReopenFile) Reopen() error {
+	file, err := f.open()
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		file.Close()
+		return os.ErrClosed
+	}
+	prev := f.file
+	f.file = file
+	return prev.Close()
+}
+
+// NotifyReopen reopens the file whenever the process receives one of sigs,
+// or SIGHUP when none are given. It stops when the file is closed.
+func (f *ReopenFile) NotifyReopen(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	f.sigOnce.Do(func() {
+		f.sigCh = make(chan os.Signal, 1)
+		signal.Notify(f.sigCh, sigs...)
+		go func() {
+			for {
+				select {
+				case <-f.sigCh:
+					f.Reopen()
+				case <-f.done:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Sync commits the file to stable storage.
+func (f *ReopenFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Close stops listening for signals and closes the file.
+func (f *ReopenFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	if f.sigCh != nil {
+		signal.Stop(f.sigCh)
+	}
+	close(f.done)
+	return f.file.Close()
+}
+
+func (f *ReopenFile) open() (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.mode)
+}
+
+// WithReopenFile configures a JSON handler writing to f and reopens f on
+// SIGHUP, so rotated files need no handler swap. Logger.Close closes f.
+func WithReopenFile(f *ReopenFile) Option {
+	return func(l *Logger) {
+		f.NotifyReopen()
+		l.SetJSONHandler(f)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.closers = append(l.closers, f)
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReopenFileReopensRenamedPathWithoutLosingWrites(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewReopenFile(path)
+	if err != nil {
+		t.Fatalf("NewReopenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	const writers, lines = 4, 200
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				fmt.Fprintf(f, "writer-%d line-%03d\n", w, i)
+			}
+		}(w)
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if err := f.Reopen(); err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	wg.Wait()
+
+	all := readFile(t, path+".1") + readFile(t, path)
+	got := strings.Split(strings.TrimSuffix(all, "\n"), "\n")
+	if len(got) != writers*lines {
+		t.Fatalf("got %d lines, want %d", len(got), writers*lines)
+	}
+	for _, line := range got {
+		if !strings.HasPrefix(line, "writer-") || !strings.Contains(line, " line-") {
+			t.Fatalf("split or corrupted line %q", line)
+		}
+	}
+}
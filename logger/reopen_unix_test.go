@@ -0,0 +1,55 @@
+//go:build unix
+
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopenFileReopensOnSIGHUP(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewReopenFile(path)
+	if err != nil {
+		t.Fatalf("NewReopenFile() error = %v", err)
+	}
+	l := NewWithOptions(WithReopenFile(f))
+
+	l.Info("before")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("file was not reopened after SIGHUP")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	l.Info("after")
+
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := f.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Fatalf("Write() after Close error = %v, want os.ErrClosed", err)
+	}
+	if got := readFile(t, path+".1"); !strings.Contains(got, `"msg":"before"`) {
+		t.Fatalf("rotated file = %q, want before record", got)
+	}
+	if got := readFile(t, path); !strings.Contains(got, `"msg":"after"`) || strings.Contains(got, "before") {
+		t.Fatalf("reopened file = %q, want only after record", got)
+	}
+}
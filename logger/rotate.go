@@ -0,0 +1,383 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RotateHourly rotates files at the start of every hour.
+	RotateHourly = time.Hour
+	// RotateDaily rotates files at the start of every day, in UTC.
+	RotateDaily = 24 * time.Hour
+
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+)
+
+// RotateOptions configures a RotatingFile.
+type RotateOptions struct {
+	// MaxSize rotates the file before a write would make it larger than
+	// MaxSize bytes. Zero disables size-based rotation.
+	MaxSize int64
+	// Interval rotates the file when a write happens in a new interval, such
+	// as RotateHourly or RotateDaily. Zero disables time-based rotation.
+	Interval time.Duration
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of them.
+	MaxBackups int
+	// MaxAge removes rotated files older than MaxAge. Zero keeps all of them.
+	MaxAge time.Duration
+	// Compress gzips rotated files in the background.
+	Compress bool
+	// Symlink, when set, is kept pointing to the current file.
+	Symlink string
+	// Mode is the permission of new files. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// RotatingFile is an io.Writer writing to a file that is rotated by size,
+// by time or on demand. Rotated files are renamed with a timestamp, such as
+// app-2026-05-02T10-00-00.000.log for app.log.
+//
+// RotatingFile is safe for concurrent use. Opening the same path more than
+// once in a process shares the underlying file, so several loggers can write
+// to it; the options of the first open apply.
+type RotatingFile struct {
+	file   *rotatingFile
+	closed bool
+	mu     sync.Mutex
+}
+
+type rotatingFile struct {
+	path string
+	opts RotateOptions
+	now  func() time.Time
+
+	mu     sync.Mutex
+	refs   int
+	closed bool // Set by the last Close.
+	file   *os.File
+	size   int64
+	period time.Time // Start of the interval the current file belongs to.
+
+	millCh chan struct{}
+	millWg sync.WaitGroup
+}
+
+var (
+	rotatingFilesMu sync.Mutex
+	rotatingFiles   = map[string]*rotatingFile{}
+)
+
+// NewRotatingFile opens path for appending, creating it and its directory if needed.
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	rotatingFilesMu.Lock()
+	defer rotatingFilesMu.Unlock()
+	f, found := rotatingFiles[abs]
+	if !found {
+		if opts.Mode == 0 {
+			opts.Mode = 0o644
+		}
+		f = &rotatingFile{
+			path:   abs,
+			opts:   opts,
+			now:    time.Now,
+			millCh: make(chan struct{}, 1),
+		}
+		if err := f.open(); err != nil {
+			return nil, err
+		}
+		rotatingFiles[abs] = f
+		f.millWg.Add(1)
+		go f.mill()
+	}
+	f.refs++
+	return &RotatingFile{file: f}, nil
+}
+
+// Write appends p to the current file, rotating it first when needed.
+func (w *RotatingFile) Write(p []byte) (int, error) {
+	if err := w.check(); err != nil {
+		return 0, err
+	}
+	return w.file.write(p)
+}
+
+// Rotate closes the current file, renames it with a timestamp and opens a new one.
+func (w *RotatingFile) Rotate() error {
+	if err := w.check(); err != nil {
+		return err
+	}
+	f := w.file
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Sync commits the current file to stable storage.
+func (w *RotatingFile) Sync() error {
+	if err := w.check(); err != nil {
+		return err
+	}
+	f := w.file
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Close releases the file. The file is closed once every RotatingFile opened
+// for its path is closed, after background compression and cleanup finish.
+func (w *RotatingFile) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	f := w.file
+	rotatingFilesMu.Lock()
+	f.refs--
+	last := f.refs == 0
+	if last {
+		delete(rotatingFiles, f.path)
+	}
+	rotatingFilesMu.Unlock()
+	if !last {
+		return nil
+	}
+
+	// Writes through other RotatingFiles that passed check may still be
+	// running, so mark f closed and stop the mill under f.mu: rotate never
+	// signals millCh once it is closed.
+	f.mu.Lock()
+	f.closed = true
+	close(f.millCh)
+	err := f.file.Close()
+	f.mu.Unlock()
+	f.millWg.Wait()
+	return err
+}
+
+func (w *RotatingFile) check() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	return nil
+}
+
+func (f *rotatingFile) write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// shouldRotate must be called with f.mu held.
+func (f *rotatingFile) shouldRotate(n int64) bool {
+	if f.opts.MaxSize > 0 && f.size > 0 && f.size+n > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.Interval > 0 && f.now().Truncate(f.opts.Interval).After(f.period)
+}
+
+// open must be called with f.mu held, or before f is shared.
+func (f *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.opts.Mode)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.period = f.now()
+	if f.opts.Interval > 0 {
+		f.period = f.period.Truncate(f.opts.Interval)
+	}
+	return f.link()
+}
+
+// rotate must be called with f.mu held.
+func (f *rotatingFile) rotate() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	if err := f.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+	if err := os.Rename(f.path, f.backupName(f.now())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	select {
+	case f.millCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (f *rotatingFile) link() error {
+	if f.opts.Symlink == "" {
+		return nil
+	}
+	tmp := f.opts.Symlink + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(f.path, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.opts.Symlink)
+}
+
+func (f *rotatingFile) prefixAndExt() (string, string) {
+	name := filepath.Base(f.path)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// backupName returns an unused name for a file rotated at t.
+func (f *rotatingFile) backupName(t time.Time) string {
+	prefix, ext := f.prefixAndExt()
+	for {
+		name := filepath.Join(filepath.Dir(f.path), prefix+t.UTC().Format(backupTimeFormat)+ext)
+		if _, err := os.Lstat(name); errors.Is(err, os.ErrNotExist) {
+			if _, err := os.Lstat(name + compressSuffix); errors.Is(err, os.ErrNotExist) {
+				return name
+			}
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// mill compresses and removes rotated files in the background.
+func (f *rotatingFile) mill() {
+	defer f.millWg.Done()
+	for range f.millCh {
+		f.millOnce()
+	}
+}
+
+type backup struct {
+	path string
+	time time.Time
+}
+
+func (f *rotatingFile) backups() ([]backup, error) {
+	dir := filepath.Dir(f.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix, ext := f.prefixAndExt()
+	var backups []backup
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), compressSuffix)
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, entry.Name()), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+func (f *rotatingFile) millOnce() error {
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	var kept []backup
+	for i, b := range backups {
+		expired := f.opts.MaxAge > 0 && f.now().Sub(b.time) > f.opts.MaxAge
+		if expired || (f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups) {
+			if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		kept = append(kept, b)
+	}
+	if f.opts.Compress {
+		for _, b := range kept {
+			if strings.HasSuffix(b.path, compressSuffix) {
+				continue
+			}
+			if err := compressFile(b.path, f.opts.Mode); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func compressFile(path string, mode os.FileMode) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(path + compressSuffix)
+		}
+	}()
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
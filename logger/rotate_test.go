@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
+
+func backupFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "app-*"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	return matches
+}
+
+func TestRotatingFileRotatesBySizeAndKeepsMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := NewRotatingFile(path, RotateOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+
+	for _, line := range []string{"line-001\n", "line-002\n", "line-003\n", "line-004\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := readFile(t, path); got != "line-004\n" {
+		t.Fatalf("current file = %q, want last line", got)
+	}
+	if got := backupFiles(t, dir); len(got) != 2 {
+		t.Fatalf("backups = %v, want 2", got)
+	}
+}
+
+func TestRotatingFileRotatesByTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := NewRotatingFile(path, RotateOptions{Interval: RotateHourly})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+	defer w.Close()
+	now := time.Date(2026, 5, 2, 10, 30, 0, 0, time.UTC)
+	w.file.mu.Lock()
+	w.file.now = func() time.Time { return now }
+	w.file.period = now.Truncate(time.Hour)
+	w.file.mu.Unlock()
+
+	w.Write([]byte("first\n"))
+	now = now.Add(time.Hour)
+	w.Write([]byte("second\n"))
+
+	if got := readFile(t, path); got != "second\n" {
+		t.Fatalf("current file = %q, want second line", got)
+	}
+	backups := backupFiles(t, dir)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], "app-2026-05-02T11-30-00.000.log") {
+		t.Fatalf("backups = %v, want one rotated at 11:30", backups)
+	}
+}
+
+func TestRotatingFileCompressesAndLinksOnDemand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	link := filepath.Join(dir, "current.log")
+	w, err := NewRotatingFile(path, RotateOptions{Compress: true, Symlink: link})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+
+	w.Write([]byte("before\n"))
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	w.Write([]byte("after\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := readFile(t, link); got != "after\n" {
+		t.Fatalf("symlinked file = %q, want current file", got)
+	}
+	backups := backupFiles(t, dir)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+		t.Fatalf("backups = %v, want one compressed file", backups)
+	}
+	file, err := os.Open(backups[0])
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	if string(data) != "before\n" {
+		t.Fatalf("compressed backup = %q, want before", data)
+	}
+}
+
+func TestRotatingFileSharedByConcurrentLoggers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	var loggers []*Logger
+	var writers []*RotatingFile
+	for i := 0; i < 2; i++ {
+		w, err := NewRotatingFile(path, RotateOptions{MaxSize: 512})
+		if err != nil {
+			t.Fatalf("NewRotatingFile() error = %v", err)
+		}
+		writers = append(writers, w)
+		loggers = append(loggers, NewWithOptions(WithJSONHandler(w), WithSource(false)))
+	}
+
+	var wg sync.WaitGroup
+	for _, l := range loggers {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Info("hello")
+			}()
+		}
+	}
+	wg.Wait()
+	for _, w := range writers {
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	lines := strings.Count(readFile(t, path), "\n")
+	for _, backup := range backupFiles(t, dir) {
+		content := readFile(t, backup)
+		for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+			if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+				t.Fatalf("expected whole records in %s, got %q", backup, line)
+			}
+		}
+		lines += strings.Count(content, "\n")
+	}
+	if lines != 40 {
+		t.Fatalf("records = %d, want 40", lines)
+	}
+}
+
+func TestRotatingFileWritesRacingClose(t *testing.T) {
+	for round := 0; round < 5; round++ {
+		w, err := NewRotatingFile(filepath.Join(t.TempDir(), "app.log"), RotateOptions{MaxSize: 1})
+		if err != nil {
+			t.Fatalf("NewRotatingFile() error = %v", err)
+		}
+		var wg, started sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			started.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					if j == 1 {
+						started.Done()
+					}
+					if _, err := w.Write([]byte("line\n")); err != nil && !errors.Is(err, os.ErrClosed) {
+						t.Errorf("Write() error = %v, want nil or os.ErrClosed", err)
+						return
+					}
+				}
+			}()
+		}
+		started.Wait()
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		wg.Wait()
+	}
+}
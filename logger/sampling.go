@@ -0,0 +1,296 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// SampledMessageKey is the key used by sampling reports for the message of the dropped records.
+	SampledMessageKey = "sampled_msg"
+	// DroppedKey is the key used by sampling reports for the number of dropped records.
+	DroppedKey = "dropped"
+)
+
+// SamplingPolicy configures how the records of a level are sampled.
+// Records are counted per message. The zero policy keeps every record.
+type SamplingPolicy struct {
+	// First is the number of records logged per message in each interval.
+	First int
+	// Thereafter logs every Thereafter-th record once First is reached.
+	// Zero drops the remaining records of the interval.
+	Thereafter int
+	// Rate, when positive, replaces First and Thereafter with a token bucket
+	// refilled with Rate tokens per second, holding up to Burst tokens.
+	Rate  float64
+	Burst int
+}
+
+// SamplingOptions configures a SamplingHandler.
+type SamplingOptions struct {
+	// Interval is the sampling window; dropped records are reported when it
+	// ends. Defaults to one second.
+	Interval time.Duration
+	// Policy applies to levels below ERROR without a policy in Levels.
+	Policy SamplingPolicy
+	// Levels overrides Policy per level. ERROR, FATAL and PANIC records are
+	// only sampled when they have a policy here.
+	Levels map[slog.Level]SamplingPolicy
+	// MaxEntries bounds the number of messages counted at once. Records of a
+	// new message are kept without sampling while it is reached. Defaults
+	// to 10000.
+	MaxEntries int
+}
+
+func (o SamplingOptions) policy(level slog.Level) (SamplingPolicy, bool) {
+	policy, found := o.Levels[level]
+	if !found {
+		if level >= slog.LevelError {
+			return SamplingPolicy{}, false
+		}
+		policy = o.Policy
+	}
+	return policy, policy != SamplingPolicy{}
+}
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+type samplingCounter struct {
+	count   int
+	tokens  float64
+	refill  time.Time
+	dropped int
+	seen    bool // Whether the counter was used in the current interval.
+}
+
+// samplingState is shared by a SamplingHandler and the handlers derived from it.
+type samplingState struct {
+	opts     SamplingOptions
+	next     slog.Handler // Undecorated handler used for reports.
+	now      func() time.Time
+	mu       sync.Mutex
+	start    time.Time
+	counters map[samplingKey]*samplingCounter
+
+	ticking   bool           // Whether the ticker goroutine runs, guarded by mu.
+	closed    bool           // Set by Close, guarded by mu.
+	ticks     sync.WaitGroup // Tracks the ticker goroutine.
+	closeOnce sync.Once
+	stop      chan struct{} // Closed by Close to stop the ticker.
+}
+
+// SamplingHandler drops part of the records of high-volume messages.
+// Dropped records are reported once per interval as a synthetic record
+// carrying SampledMessageKey and DroppedKey, by a ticker started when a
+// message is first counted. The ticker stops once no message is counted
+// anymore, and for good on Close.
+type SamplingHandler struct {
+	next  slog.Handler
+	state *samplingState
+}
+
+// NewSamplingHandler creates a SamplingHandler that sends sampled records to next.
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	return &SamplingHandler{
+		next: next,
+		state: &samplingState{
+			opts:     opts,
+			next:     next,
+			now:      time.Now,
+			counters: make(map[samplingKey]*samplingCounter),
+			stop:     make(chan struct{}),
+		},
+	}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle sends the record to the next handler unless it is sampled out.
+func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	keep, reports := h.state.sample(record.Level, record.Message)
+	var errs []error
+	for _, report := range reports {
+		if err := h.state.next.Handle(ctx, report); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if keep {
+		if err := h.next.Handle(ctx, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a SamplingHandler sharing the sampling state of h.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup returns a SamplingHandler sharing the sampling state of h.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Flush reports the records dropped so far and flushes the next handler.
+func (h *SamplingHandler) Flush(ctx context.Context) error {
+	h.state.mu.Lock()
+	reports := h.state.rollover(h.state.now())
+	h.state.mu.Unlock()
+	var errs []error
+	for _, report := range reports {
+		if err := h.state.next.Handle(ctx, report); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, FlushHandler(ctx, h.state.next))
+	return errors.Join(errs...)
+}
+
+// Close stops the ticker, reports the records dropped so far and closes the
+// next handler.
+func (h *SamplingHandler) Close(ctx context.Context) error {
+	s := h.state
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.closeOnce.Do(func() { close(s.stop) })
+	done := make(chan struct{})
+	go func() {
+		s.ticks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	err := h.Flush(ctx)
+	return errors.Join(err, CloseHandler(ctx, h.state.next))
+}
+
+// sample reports whether a record is kept, along with the reports of the
+// previous interval when it just ended.
+func (s *samplingState) sample(level slog.Level, msg string) (bool, []slog.Record) {
+	policy, sampled := s.opts.policy(level)
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var reports []slog.Record
+	if s.start.IsZero() {
+		s.start = now
+	} else if now.Sub(s.start) >= s.opts.Interval {
+		reports = s.rollover(now)
+	}
+	if !sampled {
+		return true, reports
+	}
+
+	key := samplingKey{level: level, msg: msg}
+	c, found := s.counters[key]
+	if !found {
+		if len(s.counters) >= s.opts.MaxEntries {
+			return true, reports
+		}
+		c = &samplingCounter{tokens: float64(max(policy.Burst, 1)), refill: now}
+		s.counters[key] = c
+		if !s.ticking && !s.closed {
+			s.ticking = true
+			s.ticks.Add(1)
+			go s.tick(time.NewTicker(s.opts.Interval))
+		}
+	}
+	c.seen = true
+	keep := c.take(policy, now)
+	if !keep {
+		c.dropped++
+	}
+	return keep, reports
+}
+
+// tick reports the dropped records of each interval once it ends, until no
+// message is counted anymore or Close stops it.
+func (s *samplingState) tick(ticker *time.Ticker) {
+	defer s.ticks.Done()
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			s.mu.Lock()
+			s.ticking = false
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+			now := s.now()
+			var reports []slog.Record
+			s.mu.Lock()
+			if !s.start.IsZero() && now.Sub(s.start) >= s.opts.Interval {
+				reports = s.rollover(now)
+			}
+			idle := len(s.counters) == 0
+			if idle {
+				s.ticking = false
+			}
+			s.mu.Unlock()
+			for _, report := range reports {
+				s.next.Handle(context.Background(), report)
+			}
+			if idle {
+				return
+			}
+		}
+	}
+}
+
+// rollover must be called with s.mu held.
+func (s *samplingState) rollover(now time.Time) []slog.Record {
+	var reports []slog.Record
+	for key, c := range s.counters {
+		if c.dropped > 0 {
+			report := slog.NewRecord(now, key.level, fmt.Sprintf("dropped %d records for msg %q", c.dropped, key.msg), 0)
+			report.AddAttrs(slog.String(SampledMessageKey, key.msg), slog.Int(DroppedKey, c.dropped))
+			reports = append(reports, report)
+		}
+		if !c.seen {
+			delete(s.counters, key)
+		}
+		c.count, c.dropped, c.seen = 0, 0, false
+	}
+	s.start = now
+	return reports
+}
+
+func (c *samplingCounter) take(policy SamplingPolicy, now time.Time) bool {
+	if policy.Rate > 0 {
+		c.tokens += now.Sub(c.refill).Seconds() * policy.Rate
+		c.tokens = min(c.tokens, float64(max(policy.Burst, 1)))
+		c.refill = now
+		if c.tokens < 1 {
+			return false
+		}
+		c.tokens--
+		return true
+	}
+	c.count++
+	if c.count <= policy.First {
+		return true
+	}
+	return policy.Thereafter > 0 && (c.count-policy.First)%policy.Thereafter == 0
+}
@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestSamplingHandler(buf *bytes.Buffer, opts SamplingOptions) (*SamplingHandler, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	h := NewSamplingHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: LevelTrace}), opts)
+	h.state.now = clock.Now
+	return h, clock
+}
+
+func TestSamplingHandlerKeepsFirstThenEveryMth(t *testing.T) {
+	var buf bytes.Buffer
+	h, clock := newTestSamplingHandler(&buf, SamplingOptions{
+		Interval: time.Second,
+		Policy:   SamplingPolicy{First: 2, Thereafter: 3},
+	})
+	l := slog.New(h)
+
+	for i := 0; i < 11; i++ {
+		l.Info("hot")
+	}
+	if got := strings.Count(buf.String(), "\"msg\":\"hot\""); got != 5 {
+		t.Fatalf("kept = %d, want 5; output = %q", got, buf.String())
+	}
+
+	buf.Reset()
+	clock.now = clock.now.Add(time.Second)
+	l.Info("hot")
+	output := buf.String()
+	if !strings.Contains(output, "\"msg\":\"dropped 6 records for msg \\\"hot\\\"\"") || !strings.Contains(output, "\"dropped\":6") {
+		t.Fatalf("expected dropped report, got %q", output)
+	}
+	if !strings.Contains(output, "\"msg\":\"hot\"") {
+		t.Fatalf("expected a new interval to keep the record, got %q", output)
+	}
+}
+
+func TestSamplingHandlerTokenBucket(t *testing.T) {
+	var buf bytes.Buffer
+	h, clock := newTestSamplingHandler(&buf, SamplingOptions{
+		Interval: time.Minute,
+		Policy:   SamplingPolicy{Rate: 2, Burst: 2},
+	})
+	l := slog.New(h)
+
+	for i := 0; i < 5; i++ {
+		l.Info("hot")
+	}
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	l.Info("hot")
+	l.Info("hot")
+
+	if got := strings.Count(buf.String(), "\"msg\":\"hot\""); got != 3 {
+		t.Fatalf("kept = %d, want 3; output = %q", got, buf.String())
+	}
+}
+
+func TestSamplingHandlerNeverDropsErrorsByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	h, _ := newTestSamplingHandler(&buf, SamplingOptions{
+		Policy: SamplingPolicy{First: 1},
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "failed", 0)); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+		if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), LevelFatal, "fatal", 0)); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 6 {
+		t.Fatalf("records = %d, want 6; output = %q", got, buf.String())
+	}
+}
+
+func TestSamplingHandlerSamplesErrorsWhenConfigured(t *testing.T) {
+	var buf bytes.Buffer
+	h, _ := newTestSamplingHandler(&buf, SamplingOptions{
+		Levels: map[slog.Level]SamplingPolicy{slog.LevelError: {First: 1}},
+	})
+	l := slog.New(h)
+
+	l.Error("failed")
+	l.Error("failed")
+	l.Info("info")
+	l.Info("info")
+
+	if got := strings.Count(buf.String(), "\"msg\":\"failed\""); got != 1 {
+		t.Fatalf("errors kept = %d, want 1; output = %q", got, buf.String())
+	}
+	if got := strings.Count(buf.String(), "\"msg\":\"info\""); got != 2 {
+		t.Fatalf("info kept = %d, want 2 with the zero default policy; output = %q", got, buf.String())
+	}
+}
+
+func TestSamplingHandlerReportsDroppedRecordsOnTicker(t *testing.T) {
+	var buf lockedBuffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Interval: 10 * time.Millisecond,
+		Policy:   SamplingPolicy{First: 1},
+	})
+	l := slog.New(h)
+
+	for i := 0; i < 4; i++ {
+		l.Info("hot")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "\"dropped\":3") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !strings.Contains(buf.String(), "\"dropped\":3") {
+		t.Fatalf("expected the ticker to report the dropped records, got %q", buf.String())
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	if h.state.ticking {
+		t.Fatal("expected Close to stop the ticker")
+	}
+}
+
+func TestSamplingHandlerTokenBucketWithoutBurstKeepsFirstRecord(t *testing.T) {
+	var buf bytes.Buffer
+	h, _ := newTestSamplingHandler(&buf, SamplingOptions{
+		Policy: SamplingPolicy{Rate: 1},
+	})
+	l := slog.New(h)
+
+	l.Info("hot")
+	l.Info("hot")
+	if got := strings.Count(buf.String(), "\"msg\":\"hot\""); got != 1 {
+		t.Fatalf("kept = %d, want 1; output = %q", got, buf.String())
+	}
+}
+
+func TestSamplingHandlerStopsTickerWhenIdle(t *testing.T) {
+	var buf lockedBuffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Interval: 5 * time.Millisecond,
+		Policy:   SamplingPolicy{First: 1},
+	})
+	defer h.Close(context.Background())
+	l := slog.New(h)
+
+	ticking := func() bool {
+		h.state.mu.Lock()
+		defer h.state.mu.Unlock()
+		return h.state.ticking
+	}
+	for round := 0; round < 2; round++ {
+		l.Info("hot")
+		l.Info("hot")
+		if !ticking() {
+			t.Fatalf("round %d: expected a counted message to start the ticker", round)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for ticking() && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if ticking() {
+			t.Fatalf("round %d: expected the ticker to stop once no message is counted", round)
+		}
+	}
+	if got := strings.Count(buf.String(), "\"dropped\":1"); got != 2 {
+		t.Fatalf("reports = %d, want 2; output = %q", got, buf.String())
+	}
+}
+
+func TestSamplingHandlerKeepsNewMessagesOverMaxEntries(t *testing.T) {
+	var buf bytes.Buffer
+	h, _ := newTestSamplingHandler(&buf, SamplingOptions{
+		Policy:     SamplingPolicy{First: 1},
+		MaxEntries: 1,
+	})
+	defer h.Close(context.Background())
+	l := slog.New(h)
+
+	for i := 0; i < 3; i++ {
+		l.Info("first")
+		l.Info("second")
+	}
+	if len(h.state.counters) != 1 {
+		t.Fatalf("counters = %d, want 1", len(h.state.counters))
+	}
+	if first, second := strings.Count(buf.String(), "\"msg\":\"first\""), strings.Count(buf.String(), "\"msg\":\"second\""); first != 1 || second != 3 {
+		t.Fatalf("kept first = %d and second = %d, want 1 and 3", first, second)
+	}
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordShape describes how a vendor JSON layout differs from slog's.
+type recordShape struct {
+	// replaceAttr renames and converts the built-in time, level and msg keys.
+	replaceAttr func(groups []string, attr slog.Attr) slog.Attr
+	// lift maps the record attr keys taken out of the record, wherever the
+	// logger groups put them, to the check of the value they expect. Attrs
+	// that pass it, or any value when the check is nil, are passed unresolved
+	// to fields; others stay in the record.
+	// Attrs added with WithAttrs before any group are lifted the same way.
+	lift map[string]func(slog.Value) bool
+	// fields returns the top-level fields written before the record attrs.
+	fields func(ctx context.Context, record slog.Record, lifted map[string]slog.Value) []slog.Attr
+}
+
+// shapeHandler writes records as JSON in a vendor layout. It keeps the attrs
+// and groups of WithAttrs and WithGroup itself, so the vendor fields always
+// land at the top level of the object while user attrs keep their nesting.
+type shapeHandler struct {
+	next   slog.Handler // JSON handler without attrs or groups.
+	level  slog.Leveler
+	shape  *recordShape
+	attrs  []slog.Attr
+	groups []string
+	lifted []slog.Attr // Lifted attrs of WithAttrs.
+}
+
+func newShapeHandler(w io.Writer, level slog.Leveler, shape *recordShape) *shapeHandler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	next := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       LevelTrace - 1, // Filtering is done by the shapeHandler.
+		ReplaceAttr: shape.replaceAttr,
+	})
+	return &shapeHandler{next: next, level: level, shape: shape}
+}
+
+func (h *shapeHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *shapeHandler) Handle(ctx context.Context, record slog.Record) error {
+	lifted := make(map[string]slog.Value)
+	for _, attr := range h.lifted {
+		lifted[attr.Key] = attr.Value
+	}
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		if h.lifts(attr) {
+			lifted[attr.Key] = attr.Value
+		} else {
+			attrs = append(attrs, attr)
+		}
+		return true
+	})
+
+	out := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	out.AddAttrs(h.shape.fields(ctx, record, lifted)...)
+	out.AddAttrs(InsertAttrs(h.attrs, h.groups, attrs)...)
+	return h.next.Handle(ctx, out)
+}
+
+// lifts reports whether attr is taken out of the record for the vendor fields.
+func (h *shapeHandler) lifts(attr slog.Attr) bool {
+	check, ok := h.shape.lift[attr.Key]
+	return ok && (check == nil || check(attr.Value))
+}
+
+func (h *shapeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	if len(h.groups) == 0 {
+		var rest []slog.Attr
+		for _, attr := range attrs {
+			if h.lifts(attr) {
+				clone.lifted = append(clone.lifted[:len(clone.lifted):len(clone.lifted)], attr)
+			} else {
+				rest = append(rest, attr)
+			}
+		}
+		attrs = rest
+	}
+	clone.attrs = InsertAttrs(h.attrs, h.groups, attrs)
+	return &clone
+}
+
+func (h *shapeHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// groupMembers returns the resolved members of a group value by key.
+func groupMembers(v slog.Value) map[string]slog.Value {
+	if v = v.Resolve(); v.Kind() != slog.KindGroup {
+		return nil
+	}
+	members := make(map[string]slog.Value)
+	for _, attr := range v.Group() {
+		members[attr.Key] = attr.Value.Resolve()
+	}
+	return members
+}
+
+// isGroup reports whether v is a group, such as the source group.
+func isGroup(v slog.Value) bool {
+	return v.Resolve().Kind() == slog.KindGroup
+}
+
+// isString reports whether v is a string, such as the logger name.
+func isString(v slog.Value) bool {
+	return v.Kind() == slog.KindString
+}
+
+// isError reports whether v holds an error, or the ErrorGroup logged for
+// *errors.Error.
+func isError(v slog.Value) bool {
+	switch v.Any().(type) {
+	case ErrorGroup, error:
+		return true
+	}
+	return false
+}
+
+// isTraceID reports whether v is a hex trace ID, as injected by the otel handler.
+func isTraceID(v slog.Value) bool {
+	if !isString(v) {
+		return false
+	}
+	_, err := trace.TraceIDFromHex(v.String())
+	return err == nil
+}
+
+// isSpanID reports whether v is a hex span ID, as injected by the otel handler.
+func isSpanID(v slog.Value) bool {
+	if !isString(v) {
+		return false
+	}
+	_, err := trace.SpanIDFromHex(v.String())
+	return err == nil
+}
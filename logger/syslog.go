@@ -0,0 +1,430 @@
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Facility is a syslog facility code.
+type Facility int
+
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+)
+
+const (
+	FacilityLocal0 Facility = iota + 16
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Syslog severities, from RFC 5424 section 6.2.1.
+const (
+	SeverityEmergency = iota
+	SeverityAlert
+	SeverityCritical
+	SeverityError
+	SeverityWarning
+	SeverityNotice
+	SeverityInfo
+	SeverityDebug
+)
+
+// SyslogFormat selects the syslog message format.
+type SyslogFormat int
+
+const (
+	// RFC5424 formats messages per RFC 5424, with attrs as structured data.
+	RFC5424 SyslogFormat = iota
+	// RFC3164 formats messages in the BSD format, with attrs as key=value
+	// pairs after the message.
+	RFC3164
+)
+
+// SyslogOptions configures a SyslogHandler.
+type SyslogOptions struct {
+	// Network is "udp", "tcp", "unix" or "unixgram", or any network
+	// accepted by net.Dial. Stream networks use octet-counting framing.
+	Network string
+	// Addr is the address of the syslog server or socket.
+	Addr string
+	// TLSConfig, when set, dials the stream network over TLS.
+	TLSConfig *tls.Config
+	// Facility defaults to FacilityUser; FacilityKern is reserved for the kernel.
+	Facility Facility
+	// AppName defaults to the executable name.
+	AppName string
+	// ProcID defaults to the process ID.
+	ProcID string
+	// Hostname defaults to os.Hostname.
+	Hostname string
+	// SDID is the structured-data ID holding the attrs. Defaults to "attrs@32473".
+	SDID string
+	// Format selects RFC5424 (the default) or RFC3164.
+	Format SyslogFormat
+	// Level is the minimum level handled. Defaults to INFO.
+	Level slog.Leveler
+	// MinBackoff and MaxBackoff bound the wait between reconnect attempts.
+	// They default to 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// WriteTimeout bounds each write, so a stalled server does not block
+	// the logging goroutines. The connection is dropped and reopened by the
+	// next record after a timeout. Defaults to 5s.
+	WriteTimeout time.Duration
+}
+
+// syslogConn is the connection shared by a SyslogHandler and the handlers
+// derived from it.
+type syslogConn struct {
+	opts    SyslogOptions
+	stream  bool
+	now     func() time.Time
+	mu      sync.Mutex
+	conn    net.Conn
+	closed  bool
+	backoff time.Duration
+	retryAt time.Time
+	lastErr error
+}
+
+// SyslogHandler writes records to a syslog server. The connection is opened
+// on the first record and reopened after write errors, waiting between
+// failed attempts with an exponential backoff; records handled while the
+// server is unreachable return an error and are dropped. Wrap it in an
+// AsyncHandler to keep network writes off the logging path.
+type SyslogHandler struct {
+	conn   *syslogConn
+	attrs  []slog.Attr // Preformatted with dotted group prefixes.
+	prefix string
+}
+
+// NewSyslogHandler creates a SyslogHandler sending to opts.Addr.
+func NewSyslogHandler(opts SyslogOptions) *SyslogHandler {
+	if opts.Facility == FacilityKern {
+		opts.Facility = FacilityUser
+	}
+	if opts.AppName == "" {
+		opts.AppName = filepath.Base(os.Args[0])
+	}
+	if opts.ProcID == "" {
+		opts.ProcID = strconv.Itoa(os.Getpid())
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.SDID == "" {
+		opts.SDID = "attrs@32473"
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(30*time.Second, opts.MinBackoff)
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = 5 * time.Second
+	}
+	return &SyslogHandler{conn: &syslogConn{
+		opts:   opts,
+		stream: !isDatagram(opts.Network),
+		now:    time.Now,
+	}}
+}
+
+func isDatagram(network string) bool {
+	switch network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return true
+	}
+	return false
+}
+
+// SyslogSeverity maps a level, including LevelTrace, LevelFatal and
+// LevelPanic, to a syslog severity.
+func SyslogSeverity(level slog.Level) int {
+	switch {
+	case level >= LevelPanic:
+		return SeverityAlert
+	case level >= LevelFatal:
+		return SeverityCritical
+	case level >= slog.LevelError:
+		return SeverityError
+	case level >= slog.LevelWarn:
+		return SeverityWarning
+	case level > slog.LevelInfo:
+		return SeverityNotice
+	case level == slog.LevelInfo:
+		return SeverityInfo
+	}
+	return SeverityDebug
+}
+
+// Enabled reports whether level reaches SyslogOptions.Level.
+func (h *SyslogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.conn.opts.Level.Level()
+}
+
+// Handle formats the record and writes it to the server.
+func (h *SyslogHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+record.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = appendFlatAttr(attrs, h.prefix, attr)
+		return true
+	})
+
+	var msg []byte
+	if h.conn.opts.Format == RFC3164 {
+		msg = h.conn.format3164(record, attrs)
+	} else {
+		msg = h.conn.format5424(record, attrs)
+	}
+	return h.conn.write(msg)
+}
+
+// WithAttrs returns a SyslogHandler sharing the connection of h.
+func (h *SyslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = make([]slog.Attr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(clone.attrs, h.attrs)
+	for _, attr := range attrs {
+		clone.attrs = appendFlatAttr(clone.attrs, h.prefix, attr)
+	}
+	return &clone
+}
+
+// WithGroup returns a SyslogHandler sharing the connection of h.
+func (h *SyslogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// Close closes the connection. Records handled after Close return
+// ErrHandlerClosed.
+func (h *SyslogHandler) Close(context.Context) error {
+	c := h.conn
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// appendFlatAttr appends attr to attrs, resolving it and flattening groups
+// into dotted keys.
+func appendFlatAttr(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		attr.Key = prefix + attr.Key
+		return append(attrs, attr)
+	}
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+	for _, member := range attr.Value.Group() {
+		attrs = appendFlatAttr(attrs, prefix, member)
+	}
+	return attrs
+}
+
+// attrString formats a resolved value for the text-based handlers.
+func attrString(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+	}
+	return v.String()
+}
+
+func (c *syslogConn) priority(level slog.Level) int {
+	return int(c.opts.Facility)*8 + SyslogSeverity(level)
+}
+
+func (c *syslogConn) format5424(record slog.Record, attrs []slog.Attr) []byte {
+	timestamp := "-"
+	if !record.Time.IsZero() {
+		timestamp = record.Time.Format("2006-01-02T15:04:05.000000Z07:00")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s - ",
+		c.priority(record.Level), timestamp,
+		headerField(c.opts.Hostname, 255), headerField(c.opts.AppName, 48),
+		headerField(c.opts.ProcID, 128))
+	if len(attrs) == 0 {
+		b.WriteString("-")
+	} else {
+		b.WriteString("[" + sdName(c.opts.SDID))
+		for _, attr := range attrs {
+			b.WriteString(" " + sdName(attr.Key) + `="`)
+			sdEscaper.WriteString(&b, attrString(attr.Value))
+			b.WriteString(`"`)
+		}
+		b.WriteString("]")
+	}
+	if record.Message != "" {
+		b.WriteString(" " + record.Message)
+	}
+	return []byte(b.String())
+}
+
+func (c *syslogConn) format3164(record slog.Record, attrs []slog.Attr) []byte {
+	t := record.Time
+	if t.IsZero() {
+		t = c.now()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>%s %s %s[%s]: %s",
+		c.priority(record.Level), t.Format(time.Stamp),
+		headerField(c.opts.Hostname, 255), headerField(c.opts.AppName, 32),
+		c.opts.ProcID, record.Message)
+	for _, attr := range attrs {
+		value := attrString(attr.Value)
+		if value == "" || strings.ContainsAny(value, " \"=\\\n") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(" " + attr.Key + "=" + value)
+	}
+	return []byte(b.String())
+}
+
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// headerField returns s restricted to printable ASCII without spaces and
+// truncated to limit, or the nil value "-" when empty.
+func headerField(s string, limit int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "-"
+	}
+	if len(s) > limit {
+		s = s[:limit]
+	}
+	return s
+}
+
+// sdName returns s as a valid SD-NAME: printable ASCII without '=', ' ',
+// ']' or '"', at most 32 characters.
+func sdName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "_"
+	}
+	if len(s) > 32 {
+		s = s[:32]
+	}
+	return s
+}
+
+func (c *syslogConn) write(msg []byte) error {
+	if c.stream {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrHandlerClosed
+	}
+	var err error
+	// A write on a connection the server already dropped may fail only
+	// once the connection is found closed; retry it on a fresh connection.
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if err = c.connect(); err != nil {
+				return err
+			}
+		}
+		c.conn.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout))
+		if _, err = c.conn.Write(msg); err == nil {
+			return nil
+		}
+		c.conn.Close()
+		c.conn = nil
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			// Part of the frame may have been sent; the record is dropped.
+			return fmt.Errorf("logger: syslog write: %w", err)
+		}
+	}
+	return err
+}
+
+func (c *syslogConn) connect() error {
+	now := c.now()
+	if now.Before(c.retryAt) {
+		return fmt.Errorf("logger: syslog reconnect delayed after error: %w", c.lastErr)
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var conn net.Conn
+	var err error
+	if c.opts.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, c.opts.Network, c.opts.Addr, c.opts.TLSConfig)
+	} else {
+		conn, err = dialer.Dial(c.opts.Network, c.opts.Addr)
+	}
+	if err != nil {
+		c.backoff = min(max(2*c.backoff, c.opts.MinBackoff), c.opts.MaxBackoff)
+		c.retryAt = now.Add(c.backoff)
+		c.lastErr = err
+		return err
+	}
+	c.conn = conn
+	c.backoff = 0
+	c.retryAt = time.Time{}
+	c.lastErr = nil
+	return nil
+}
@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readUDP(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	buf := make([]byte, 64<<10)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	return string(buf[:n])
+}
+
+// readFrame reads one octet-counted message.
+func readFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	length, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatalf("read frame length error = %v", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		t.Fatalf("frame length %q error = %v", length, err)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("read frame error = %v", err)
+	}
+	return string(buf)
+}
+
+func TestSyslogHandlerSendsRFC5424OverUDP(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer server.Close()
+
+	h := NewSyslogHandler(SyslogOptions{
+		Network:  "udp",
+		Addr:     server.LocalAddr().String(),
+		Facility: FacilityLocal0,
+		AppName:  "billing",
+		ProcID:   "42",
+		Hostname: "host-1",
+		Level:    LevelTrace,
+	})
+	defer h.Close(context.Background())
+	l := NewWithOptions(WithHandler(h), WithLevel(LevelTrace), WithSource(false))
+
+	l.With("service", "api").WithGroup("req").Info("paid", "id", 7, "note", `a "quoted" ]value\`)
+	got := readUDP(t, server)
+	wantPrefix := "<134>1 "
+	wantSuffix := ` host-1 billing 42 - [attrs@32473 service="api" req.id="7" req.note="a \"quoted\" \]value\\"] paid`
+	if !strings.HasPrefix(got, wantPrefix) || !strings.HasSuffix(got, wantSuffix) {
+		t.Fatalf("message = %q, want prefix %q and suffix %q", got, wantPrefix, wantSuffix)
+	}
+
+	for _, tt := range []struct {
+		log  func(string, ...any)
+		want string
+	}{
+		{l.Print, "<135>1 "},
+		{l.Debug, "<135>1 "},
+		{l.Warn, "<132>1 "},
+		{l.Error, "<131>1 "},
+	} {
+		tt.log("msg")
+		if got := readUDP(t, server); !strings.HasPrefix(got, tt.want) || !strings.HasSuffix(got, " - msg") {
+			t.Fatalf("message = %q, want prefix %q without structured data", got, tt.want)
+		}
+	}
+}
+
+func TestSyslogSeverityMapsCustomLevels(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  int
+	}{
+		{LevelTrace, SeverityDebug},
+		{slog.LevelDebug, SeverityDebug},
+		{slog.LevelInfo, SeverityInfo},
+		{slog.LevelInfo + 2, SeverityNotice},
+		{slog.LevelWarn, SeverityWarning},
+		{slog.LevelError, SeverityError},
+		{LevelFatal, SeverityCritical},
+		{LevelPanic, SeverityAlert},
+	}
+	for _, tt := range tests {
+		if got := SyslogSeverity(tt.level); got != tt.want {
+			t.Errorf("SyslogSeverity(%v) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSyslogHandlerFormatsRFC3164(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer server.Close()
+
+	h := NewSyslogHandler(SyslogOptions{
+		Network:  "udp",
+		Addr:     server.LocalAddr().String(),
+		Format:   RFC3164,
+		AppName:  "billing",
+		ProcID:   "42",
+		Hostname: "host-1",
+	})
+	defer h.Close(context.Background())
+
+	record := slog.NewRecord(time.Date(2024, 3, 5, 7, 8, 9, 0, time.UTC), LevelFatal, "down", 0)
+	record.AddAttrs(slog.String("reason", "disk full"), slog.Int("code", 28))
+	if err := h.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	want := `<10>Mar  5 07:08:09 host-1 billing[42]: down reason="disk full" code=28`
+	if got := readUDP(t, server); got != want {
+		t.Fatalf("message = %q, want %q", got, want)
+	}
+}
+
+func TestSyslogHandlerFramesTCPAndReconnectsWithBackoff(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := listener.Addr().String()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	h := NewSyslogHandler(SyslogOptions{Network: "tcp", Addr: addr, MinBackoff: time.Second})
+	h.conn.now = clock.Now
+	defer h.Close(context.Background())
+
+	record := func(msg string) slog.Record {
+		return slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+	}
+	if err := h.Handle(context.Background(), record("one")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if err := h.Handle(context.Background(), record("two")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	r := bufio.NewReader(conn)
+	for _, want := range []string{" one", " two"} {
+		if got := readFrame(t, r); !strings.HasSuffix(got, want) {
+			t.Fatalf("frame = %q, want suffix %q", got, want)
+		}
+	}
+
+	// Take the server down: the next records fail and reconnects back off.
+	conn.Close()
+	listener.Close()
+	var failed bool
+	for i := 0; i < 10 && !failed; i++ {
+		failed = h.Handle(context.Background(), record("lost")) != nil
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !failed {
+		t.Fatal("Handle() succeeded with the server down")
+	}
+
+	listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen again on %s: %v", addr, err)
+	}
+	defer listener.Close()
+	if err := h.Handle(context.Background(), record("early")); err == nil {
+		t.Fatal("Handle() reconnected before the backoff elapsed")
+	}
+	clock.now = clock.now.Add(time.Second)
+	if err := h.Handle(context.Background(), record("three")); err != nil {
+		t.Fatalf("Handle() after backoff error = %v", err)
+	}
+	conn, err = listener.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	defer conn.Close()
+	if got := readFrame(t, bufio.NewReader(conn)); !strings.HasSuffix(got, " three") {
+		t.Fatalf("frame after reconnect = %q, want three", got)
+	}
+
+	h.Close(context.Background())
+	if err := h.Handle(context.Background(), record("closed")); err != ErrHandlerClosed {
+		t.Fatalf("Handle() after Close error = %v, want ErrHandlerClosed", err)
+	}
+}
+
+func TestSyslogHandlerDropsStalledConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn // Never read, so the socket buffers fill up.
+		}
+	}()
+
+	h := NewSyslogHandler(SyslogOptions{Network: "tcp", Addr: listener.Addr().String(), WriteTimeout: 50 * time.Millisecond})
+	defer h.Close(context.Background())
+	large := slog.NewRecord(time.Now(), slog.LevelInfo, strings.Repeat("x", 1<<20), 0)
+	for i := 0; i < 100 && err == nil; i++ {
+		err = h.Handle(context.Background(), large)
+	}
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Handle() error = %v, want a write timeout", err)
+	}
+	if h.conn.conn != nil {
+		t.Fatal("expected the stalled connection to be dropped")
+	}
+	first := <-accepted
+	defer first.Close()
+
+	// The next record opens a new connection.
+	h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "after", 0))
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a new connection after the timeout")
+	}
+}
@@ -9,7 +9,7 @@ func Print(args ...interface{}) {
 
 func PrintC(ctx context.Context, args ...interface{}) {
 	msg, attrs := validateArgs(args...)
-	std.PrintContext(ctx, msg, attrs...)
+	FromContext(ctx).PrintContext(ctx, msg, attrs...)
 }
 
 func Info(args ...interface{}) {
@@ -19,5 +19,5 @@ func Info(args ...interface{}) {
 
 func InfoC(ctx context.Context, args ...interface{}) {
 	msg, attrs := validateArgs(args...)
-	std.InfoContext(ctx, msg, attrs...)
+	FromContext(ctx).InfoContext(ctx, msg, attrs...)
 }
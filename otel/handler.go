@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jgolang/log/logger"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
@@ -23,6 +24,9 @@ const (
 	// SpanEventKey is the prefix key used by the Otel handler
 	// to inject the log record in the recording span, as a span event.
 	SpanEventKey = "log"
+	// ExceptionEventName is the name of the span event recorded by the
+	// Otel handler for each error attribute, per the OTel semantic conventions.
+	ExceptionEventName = "exception"
 )
 
 // OtelHandler is an implementation of slog's Handler interface.
@@ -33,7 +37,8 @@ const (
 // 2. Adding otel context baggage members to the log record.
 // 3. Setting slog record as otel span event.
 // 4. Adding slog record attributes to the otel span event.
-// 5. Setting span status based on slog record level (only if >= slog.LevelError).
+// 5. Recording error attributes, also in groups and WithAttrs, as exception span events.
+// 6. Setting span status based on slog record level (only if >= slog.LevelError).
 type OtelHandler struct {
 	// Next represents the next handler in the chain.
 	Next slog.Handler
@@ -43,6 +48,13 @@ type OtelHandler struct {
 	NoTraceEvents bool
 	// BaggageFilter can allow, drop, or redact baggage members before they are logged.
 	BaggageFilter BaggageFilter
+	// NoExceptionEvents determines whether to record an exception event for every error attribute.
+	NoExceptionEvents bool
+	// RecordErrors determines whether exception events are recorded with span.RecordError,
+	// letting the tracer set exception.type and exception.message.
+	RecordErrors bool
+
+	errAttrs []slog.Attr // Error attributes added with WithAttrs.
 }
 
 type OtelHandlerOpt func(handler *OtelHandler)
@@ -67,6 +79,20 @@ func WithNoTraceEvents(noTraceEvents bool) OtelHandlerOpt {
 	}
 }
 
+// WithNoExceptionEvents returns an OtelHandlerOpt, which sets the NoExceptionEvents flag
+func WithNoExceptionEvents(noExceptionEvents bool) OtelHandlerOpt {
+	return func(handler *OtelHandler) {
+		handler.NoExceptionEvents = noExceptionEvents
+	}
+}
+
+// WithRecordErrors returns an OtelHandlerOpt, which sets the RecordErrors flag
+func WithRecordErrors(recordErrors bool) OtelHandlerOpt {
+	return func(handler *OtelHandler) {
+		handler.RecordErrors = recordErrors
+	}
+}
+
 // WithBaggageFilter configures a baggage filter for selective logging/redaction.
 func WithBaggageFilter(filter BaggageFilter) OtelHandlerOpt {
 	return func(handler *OtelHandler) {
@@ -166,6 +192,19 @@ func (h OtelHandler) Handle(ctx context.Context, record slog.Record) error {
 		span.AddEvent(spanKey, trace.WithAttributes(eventAttrs...))
 	}
 
+	if !h.NoExceptionEvents {
+		// Adding error attributes, including the ones of WithAttrs and
+		// groups, as exception span events.
+		attrs := make([]slog.Attr, 0, record.NumAttrs())
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs = append(attrs, attr)
+			return true
+		})
+		for _, attr := range append(h.errAttrs[:len(h.errAttrs):len(h.errAttrs)], errorAttrs(attrs)...) {
+			h.recordException(span, attr)
+		}
+	}
+
 	// Adding span info to log record.
 	spanContext := span.SpanContext()
 	if spanContext.HasTraceID() {
@@ -190,20 +229,26 @@ func (h OtelHandler) Handle(ctx context.Context, record slog.Record) error {
 // WithAttrs returns a new Otel whose attributes consists of handler's attributes followed by attrs.
 func (h OtelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return OtelHandler{
-		Next:          h.Next.WithAttrs(attrs),
-		NoBaggage:     h.NoBaggage,
-		NoTraceEvents: h.NoTraceEvents,
-		BaggageFilter: h.BaggageFilter,
+		Next:              h.Next.WithAttrs(attrs),
+		NoBaggage:         h.NoBaggage,
+		NoTraceEvents:     h.NoTraceEvents,
+		BaggageFilter:     h.BaggageFilter,
+		NoExceptionEvents: h.NoExceptionEvents,
+		RecordErrors:      h.RecordErrors,
+		errAttrs:          append(h.errAttrs[:len(h.errAttrs):len(h.errAttrs)], errorAttrs(attrs)...),
 	}
 }
 
 // WithGroup returns a new Otel with a group, provided the group's name.
 func (h OtelHandler) WithGroup(name string) slog.Handler {
 	return OtelHandler{
-		Next:          h.Next.WithGroup(name),
-		NoBaggage:     h.NoBaggage,
-		NoTraceEvents: h.NoTraceEvents,
-		BaggageFilter: h.BaggageFilter,
+		Next:              h.Next.WithGroup(name),
+		NoBaggage:         h.NoBaggage,
+		NoTraceEvents:     h.NoTraceEvents,
+		BaggageFilter:     h.BaggageFilter,
+		NoExceptionEvents: h.NoExceptionEvents,
+		RecordErrors:      h.RecordErrors,
+		errAttrs:          h.errAttrs,
 	}
 }
 
@@ -213,6 +258,57 @@ func (h OtelHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return h.Next.Enabled(ctx, level)
 }
 
+// Flush flushes the next handler if it buffers records.
+func (h OtelHandler) Flush(ctx context.Context) error {
+	return logger.FlushHandler(ctx, h.Next)
+}
+
+// Close closes the next handler if it holds resources, or flushes it otherwise.
+func (h OtelHandler) Close(ctx context.Context) error {
+	return logger.CloseHandler(ctx, h.Next)
+}
+
+// recordException adds an exception event to span if attr holds an error,
+// either a plain one or the group logged for *errors.Error, whose origin
+// frames become exception.stacktrace.
+func (h OtelHandler) recordException(span trace.Span, attr slog.Attr) {
+	err := attrError(attr)
+	if err == nil {
+		return
+	}
+	details := logger.ParseErrorAttr(attr.Value)
+
+	var attrs []attribute.KeyValue
+	if details.Stack != "" {
+		attrs = append(attrs, attribute.String("exception.stacktrace", details.Stack))
+	}
+	if h.RecordErrors {
+		span.RecordError(err, trace.WithAttributes(attrs...))
+		return
+	}
+	attrs = append([]attribute.KeyValue{
+		attribute.String("exception.type", details.Type),
+		attribute.String("exception.message", details.Message),
+	}, attrs...)
+	span.AddEvent(ExceptionEventName, trace.WithAttributes(attrs...))
+}
+
+// errorAttrs returns the attrs holding an error among attrs and, at any
+// depth, inside their groups.
+func errorAttrs(attrs []slog.Attr) []slog.Attr {
+	var found []slog.Attr
+	for _, attr := range attrs {
+		if attrError(attr) != nil {
+			found = append(found, attr)
+			continue
+		}
+		if value := attr.Value.Resolve(); value.Kind() == slog.KindGroup {
+			found = append(found, errorAttrs(value.Group())...)
+		}
+	}
+	return found
+}
+
 // slogAttrToOtelAttr converts a slog attribute to an OTel one.
 // Note: returns an empty attribute if the provided slog attribute is empty.
 func (h OtelHandler) slogAttrToOtelAttr(attr slog.Attr, groupKeys ...string) []attribute.KeyValue {
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"strings"
 	"testing"
 
+	"github.com/jgolang/log/logger"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
@@ -26,6 +29,7 @@ type recordingSpan struct {
 
 	spanContext       trace.SpanContext
 	events            []recordedEvent
+	recordedErrors    []error
 	statusCode        codes.Code
 	statusDescription string
 }
@@ -47,6 +51,11 @@ func (s *recordingSpan) AddEvent(name string, options ...trace.EventOption) {
 	})
 }
 
+func (s *recordingSpan) RecordError(err error, options ...trace.EventOption) {
+	s.recordedErrors = append(s.recordedErrors, err)
+	s.AddEvent(ExceptionEventName, options...)
+}
+
 func (s *recordingSpan) SetStatus(code codes.Code, description string) {
 	s.statusCode = code
 	s.statusDescription = description
@@ -225,6 +234,96 @@ func TestHandleAddsTraceCorrelationSpanEventAndErrorStatus(t *testing.T) {
 	}
 }
 
+func TestHandleRecordsErrorsAsExceptionEvents(t *testing.T) {
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	logger := slog.New(New(slog.NewJSONHandler(io.Discard, nil)))
+
+	logger.ErrorContext(ctx, "failed", "err", errDeclined, "attempt", 2)
+
+	if len(span.events) != 2 {
+		t.Fatalf("events len = %d, want log and exception events", len(span.events))
+	}
+	event := span.events[1]
+	if event.name != ExceptionEventName {
+		t.Fatalf("event name = %q, want %s", event.name, ExceptionEventName)
+	}
+	if got := attrValue(event.attrs, "exception.type"); got != "*errors.errorString" {
+		t.Errorf("exception.type = %q, want *errors.errorString", got)
+	}
+	if got := attrValue(event.attrs, "exception.message"); got != "card declined" {
+		t.Errorf("exception.message = %q, want card declined", got)
+	}
+}
+
+func TestHandleRecordsErrorGroupStackTrace(t *testing.T) {
+	group := logger.ErrorGroup{Err: errDeclined, Value: slog.GroupValue(
+		slog.String("code", "PAY001"),
+		slog.Group("origin", slog.String("frame_0", "billing.go:12"), slog.String("frame_1", "main.go:8")),
+	)}
+
+	for _, tt := range []struct {
+		name         string
+		opts         []OtelHandlerOpt
+		wantType     string
+		recordErrors int
+	}{
+		{name: "event", wantType: "*errors.errorString"},
+		{name: "record error", opts: []OtelHandlerOpt{WithRecordErrors(true)}, recordErrors: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			span := &recordingSpan{}
+			ctx := trace.ContextWithSpan(context.Background(), span)
+			handler := New(slog.NewJSONHandler(io.Discard, nil), append(tt.opts, WithNoTraceEvents(true))...)
+
+			slog.New(handler).ErrorContext(ctx, "failed", "error", group)
+
+			if len(span.events) != 1 || span.events[0].name != ExceptionEventName {
+				t.Fatalf("events = %+v, want one exception event", span.events)
+			}
+			attrs := span.events[0].attrs
+			if got := attrValue(attrs, "exception.stacktrace"); got != "billing.go:12\nmain.go:8" {
+				t.Errorf("exception.stacktrace = %q", got)
+			}
+			if got := attrValue(attrs, "exception.type"); got != tt.wantType {
+				t.Errorf("exception.type = %q, want %q", got, tt.wantType)
+			}
+			if len(span.recordedErrors) != tt.recordErrors {
+				t.Errorf("RecordError calls = %d, want %d", len(span.recordedErrors), tt.recordErrors)
+			}
+		})
+	}
+}
+
+func TestHandleRecordsErrorsInGroupsAndWithAttrs(t *testing.T) {
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	logger := slog.New(New(slog.NewJSONHandler(io.Discard, nil), WithNoTraceEvents(true)))
+
+	logger.With("cause", errors.New("timeout")).WithGroup("payment").ErrorContext(ctx, "failed",
+		slog.Group("gateway", "err", errDeclined))
+
+	var messages []string
+	for _, event := range span.events {
+		messages = append(messages, attrValue(event.attrs, "exception.message"))
+	}
+	if got := strings.Join(messages, ","); got != "timeout,card declined" {
+		t.Fatalf("exception messages = %q, want timeout,card declined", got)
+	}
+}
+
+func TestWithNoExceptionEvents(t *testing.T) {
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	handler := New(slog.NewJSONHandler(io.Discard, nil), WithNoExceptionEvents(true))
+
+	slog.New(handler).WithGroup("payment").ErrorContext(ctx, "failed", "err", errDeclined)
+
+	if len(span.events) != 1 || span.events[0].name != "log.error" {
+		t.Fatalf("events = %+v, want only the log event", span.events)
+	}
+}
+
 func attrValue(attrs []attribute.KeyValue, key string) string {
 	for _, attr := range attrs {
 		if string(attr.Key) == key {
@@ -233,3 +332,33 @@ func attrValue(attrs []attribute.KeyValue, key string) string {
 	}
 	return ""
 }
+
+func TestDatadogHandlerConvertsInjectedIDs(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	span := &recordingSpan{
+		spanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}),
+	}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	var buf bytes.Buffer
+	slog.New(New(logger.NewDatadogHandler(&buf, logger.DatadogOptions{}))).InfoContext(ctx, "served")
+
+	var entry struct {
+		DD struct {
+			TraceID string `json:"trace_id"`
+			SpanID  string `json:"span_id"`
+		} `json:"dd"`
+		TraceID *string `json:"trace_id"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; output = %q", err, buf.String())
+	}
+	// Lower 64 bits of the trace ID, 0x090a0b0c0d0e0f10, and the span ID 0x0102030405060708.
+	if entry.DD.TraceID != "651345242494996240" || entry.DD.SpanID != "72623859790382856" {
+		t.Fatalf("dd = %+v, want decimal lower 64-bit IDs", entry.DD)
+	}
+	if entry.TraceID != nil {
+		t.Fatalf("output = %q, want trace_id moved under dd", buf.String())
+	}
+}
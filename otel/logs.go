@@ -0,0 +1,311 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/jgolang/log/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// LogsHandler is an implementation of slog's Handler interface that emits
+// every record as an OTel log record through an OTel log.Logger, so logs
+// reach the configured LoggerProvider and its exporters. It maps:
+//
+// 1. The message to the body and the record time to the timestamp.
+// 2. The level to the severity number, from TRACE to FATAL with PANIC as
+// FATAL2, and to the severity text with the custom level names.
+// 3. The source group to the code.function, code.filepath and code.lineno
+// attributes.
+// 4. Other attributes keeping their types: groups become maps, slices
+// become slices and byte slices become bytes. The first error attribute is
+// also set as the record error.
+//
+// The trace and span IDs and trace flags are taken from the span in the
+// context by the LoggerProvider.
+type LogsHandler struct {
+	// Provider creates the OTel logger. Defaults to the global LoggerProvider.
+	Provider log.LoggerProvider
+	// Version is the instrumentation scope version.
+	Version string
+	// SchemaURL is the instrumentation scope schema URL.
+	SchemaURL string
+
+	logger log.Logger
+	attrs  []slog.Attr
+	groups []string
+}
+
+// LogsHandlerOpt configures a LogsHandler.
+type LogsHandlerOpt func(handler *LogsHandler)
+
+// WithLoggerProvider returns a LogsHandlerOpt, which sets the LoggerProvider.
+func WithLoggerProvider(provider log.LoggerProvider) LogsHandlerOpt {
+	return func(handler *LogsHandler) {
+		handler.Provider = provider
+	}
+}
+
+// WithInstrumentationVersion returns a LogsHandlerOpt, which sets the
+// instrumentation scope version.
+func WithInstrumentationVersion(version string) LogsHandlerOpt {
+	return func(handler *LogsHandler) {
+		handler.Version = version
+	}
+}
+
+// WithSchemaURL returns a LogsHandlerOpt, which sets the instrumentation
+// scope schema URL.
+func WithSchemaURL(schemaURL string) LogsHandlerOpt {
+	return func(handler *LogsHandler) {
+		handler.SchemaURL = schemaURL
+	}
+}
+
+// NewLogsHandler creates a LogsHandler emitting through the OTel logger of
+// the instrumentation scope name.
+func NewLogsHandler(name string, opts ...LogsHandlerOpt) *LogsHandler {
+	h := &LogsHandler{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.Provider == nil {
+		h.Provider = global.GetLoggerProvider()
+	}
+	var loggerOpts []log.LoggerOption
+	if h.Version != "" {
+		loggerOpts = append(loggerOpts, log.WithInstrumentationVersion(h.Version))
+	}
+	if h.SchemaURL != "" {
+		loggerOpts = append(loggerOpts, log.WithSchemaURL(h.SchemaURL))
+	}
+	h.logger = h.Provider.Logger(name, loggerOpts...)
+	return h
+}
+
+// Severity maps a level to an OTel severity number: TRACE to TRACE, DEBUG
+// to DEBUG, INFO to INFO, WARN to WARN, ERROR to ERROR, FATAL to FATAL and
+// PANIC to FATAL2. Levels in between map to the numbers in between.
+func Severity(level slog.Level) log.Severity {
+	severity := int(level) + int(log.SeverityInfo)
+	return log.Severity(min(max(severity, int(log.SeverityTrace1)), int(log.SeverityFatal4)))
+}
+
+// SeverityText returns the level name, including TRACE, FATAL and PANIC.
+func SeverityText(level slog.Level) string {
+	if name, ok := logger.LevelNames[level]; ok {
+		return name
+	}
+	return level.String()
+}
+
+// Enabled reports whether the OTel logger emits records at level.
+func (h *LogsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(ctx, log.EnabledParameters{Severity: Severity(level)})
+}
+
+// Handle converts the record and emits it with ctx, which carries the span.
+func (h *LogsHandler) Handle(ctx context.Context, record slog.Record) error {
+	var out log.Record
+	out.SetTimestamp(record.Time)
+	out.SetObservedTimestamp(time.Now())
+	out.SetBody(attribute.StringValue(record.Message))
+	out.SetSeverity(Severity(record.Level))
+	out.SetSeverityText(SeverityText(record.Level))
+
+	kvs, err := convertRecord(record, h.attrs, h.groups)
+	if err != nil {
+		out.SetErr(err)
+	}
+	out.AddAttributes(kvs...)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	h.logger.Emit(ctx, out)
+	return nil
+}
+
+// WithAttrs returns a LogsHandler emitting attrs with every record.
+func (h *LogsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = logger.InsertAttrs(h.attrs, h.groups, attrs)
+	return &clone
+}
+
+// WithGroup returns a LogsHandler nesting the following attributes in a map
+// attribute named name.
+func (h *LogsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// Flush flushes the pending records of the Provider if it supports it, as
+// the SDK LoggerProvider does.
+func (h *LogsHandler) Flush(ctx context.Context) error {
+	if f, ok := h.Provider.(interface{ ForceFlush(context.Context) error }); ok {
+		return f.ForceFlush(ctx)
+	}
+	return nil
+}
+
+// Close shuts the Provider down if it supports it, exporting the pending
+// records, or flushes it otherwise. Records handled after Close are dropped
+// by the Provider.
+func (h *LogsHandler) Close(ctx context.Context) error {
+	if s, ok := h.Provider.(interface{ Shutdown(context.Context) error }); ok {
+		return s.Shutdown(ctx)
+	}
+	return h.Flush(ctx)
+}
+
+// convertRecord returns the attributes of record nested in the groups, after
+// the handler attrs, and the first error attribute.
+func convertRecord(record slog.Record, handlerAttrs []slog.Attr, groups []string) ([]attribute.KeyValue, error) {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	var kvs []attribute.KeyValue
+	var err error
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "source" && attr.Value.Kind() == slog.KindGroup {
+			kvs = append(kvs, codeAttributes(attr.Value.Group())...)
+			return true
+		}
+		if err == nil {
+			err = attrError(attr)
+		}
+		attrs = append(attrs, attr)
+		return true
+	})
+	for _, attr := range logger.InsertAttrs(handlerAttrs, groups, attrs) {
+		// A source group can also come from WithAttrs before any group.
+		if attr.Key == "source" && attr.Value.Kind() == slog.KindGroup {
+			kvs = append(kvs, codeAttributes(attr.Value.Group())...)
+			continue
+		}
+		if kv, ok := convertAttr(attr); ok {
+			kvs = append(kvs, kv)
+		}
+	}
+	return kvs, err
+}
+
+// attrError returns the error held by attr, if any.
+func attrError(attr slog.Attr) error {
+	switch v := attr.Value.Any().(type) {
+	case logger.ErrorGroup:
+		return v.Err
+	case error:
+		return v
+	}
+	return nil
+}
+
+// codeAttributes maps the source group to the code semantic conventions.
+func codeAttributes(source []slog.Attr) []attribute.KeyValue {
+	var kvs []attribute.KeyValue
+	for _, attr := range source {
+		switch attr.Key {
+		case "func":
+			kvs = append(kvs, attribute.String("code.function", attr.Value.String()))
+		case "file":
+			kvs = append(kvs, attribute.String("code.filepath", attr.Value.String()))
+		case "line":
+			kvs = append(kvs, attribute.Int64("code.lineno", attr.Value.Int64()))
+		case "stack_trace":
+			if kv, ok := convertAttr(attr); ok {
+				kvs = append(kvs, kv)
+			}
+		}
+	}
+	return kvs
+}
+
+// convertAttr converts attr to an OTel key-value, dropping empty attrs and
+// groups.
+func convertAttr(attr slog.Attr) (attribute.KeyValue, bool) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup && len(attr.Value.Group()) == 0 {
+		return attribute.KeyValue{}, false
+	}
+	kv := attribute.KeyValue{Key: attribute.Key(attr.Key), Value: convertValue(attr.Value)}
+	return kv, kv.Valid()
+}
+
+func convertValue(v slog.Value) attribute.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return attribute.StringValue(v.String())
+	case slog.KindInt64:
+		return attribute.Int64Value(v.Int64())
+	case slog.KindUint64:
+		if u := v.Uint64(); u <= math.MaxInt64 {
+			return attribute.Int64Value(int64(u))
+		}
+		return attribute.StringValue(v.String())
+	case slog.KindFloat64:
+		return attribute.Float64Value(v.Float64())
+	case slog.KindBool:
+		return attribute.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return attribute.Int64Value(v.Duration().Nanoseconds())
+	case slog.KindTime:
+		return attribute.Int64Value(v.Time().UnixNano())
+	case slog.KindGroup:
+		kvs := make([]attribute.KeyValue, 0, len(v.Group()))
+		for _, attr := range v.Group() {
+			if kv, ok := convertAttr(attr); ok {
+				kvs = append(kvs, kv)
+			}
+		}
+		return attribute.MapValue(kvs...)
+	}
+	return convertAny(v.Any())
+}
+
+func convertAny(value any) attribute.Value {
+	switch val := value.(type) {
+	case nil:
+		return attribute.Value{}
+	case []byte:
+		return attribute.ByteSliceValue(val)
+	case error:
+		return attribute.StringValue(val.Error())
+	case fmt.Stringer:
+		return attribute.StringValue(val.String())
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		values := make([]attribute.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, convertValue(slog.AnyValue(rv.Index(i).Interface())))
+		}
+		return attribute.SliceValue(values...)
+	case reflect.Map:
+		kvs := make([]attribute.KeyValue, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			kvs = append(kvs, attribute.KeyValue{
+				Key:   attribute.Key(fmt.Sprint(iter.Key().Interface())),
+				Value: convertValue(slog.AnyValue(iter.Value().Interface())),
+			})
+		}
+		return attribute.MapValue(kvs...)
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return attribute.Value{}
+		}
+		return convertValue(slog.AnyValue(rv.Elem().Interface()))
+	}
+	return attribute.StringValue(fmt.Sprintf("%+v", value))
+}
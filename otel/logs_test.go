@@ -0,0 +1,164 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jgolang/log/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errDeclined = errors.New("card declined")
+
+// memoryExporter keeps the exported records in memory.
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		e.records = append(e.records, record.Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryExporter) ForceFlush(context.Context) error { return nil }
+
+func newTestLogsHandler(t *testing.T, opts ...LogsHandlerOpt) (*LogsHandler, *memoryExporter) {
+	t.Helper()
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return NewLogsHandler("github.com/acme/billing", append([]LogsHandlerOpt{WithLoggerProvider(provider)}, opts...)...), exporter
+}
+
+func recordAttrs(record sdklog.Record) map[string]attribute.Value {
+	attrs := make(map[string]attribute.Value)
+	record.WalkAttributes(func(kv attribute.KeyValue) bool {
+		attrs[string(kv.Key)] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestLogsHandlerEmitsOtelLogRecords(t *testing.T) {
+	handler, exporter := newTestLogsHandler(t, WithInstrumentationVersion("1.2.0"))
+	l := logger.NewWithOptions(logger.WithHandler(handler), logger.WithLevel(logger.LevelTrace))
+	l.SetCalldepth(6)
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	before := time.Now()
+	l.With("service", "api").WithGroup("req").PrintContext(ctx, "charged",
+		"id", 7,
+		"amount", 12.5,
+		"ok", true,
+		"took", 1500*time.Millisecond,
+		"payload", []byte("raw"),
+		"tags", []string{"a", "b"},
+		"error", errDeclined,
+	)
+	if len(exporter.records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(exporter.records))
+	}
+	record := exporter.records[0]
+
+	if record.Body().AsString() != "charged" || record.Severity() != log.SeverityTrace1 || record.SeverityText() != "TRACE" {
+		t.Fatalf("body %q severity %v %q, want charged TRACE1 TRACE", record.Body().AsString(), record.Severity(), record.SeverityText())
+	}
+	if record.Timestamp().Before(before) || record.ObservedTimestamp().Before(record.Timestamp()) {
+		t.Fatalf("timestamp %v observed %v, want both after %v", record.Timestamp(), record.ObservedTimestamp(), before)
+	}
+	if record.TraceID() != traceID || record.SpanID() != spanID || record.TraceFlags() != trace.FlagsSampled {
+		t.Fatalf("trace %v span %v flags %v, want the span context", record.TraceID(), record.SpanID(), record.TraceFlags())
+	}
+	if scope := record.InstrumentationScope(); scope.Name != "github.com/acme/billing" || scope.Version != "1.2.0" {
+		t.Fatalf("scope = %+v", scope)
+	}
+
+	attrs := recordAttrs(record)
+	if attrs["service"].AsString() != "api" || attrs["code.filepath"].AsString() != "logs_test.go" ||
+		attrs["code.function"].AsString() != "TestLogsHandlerEmitsOtelLogRecords" || attrs["code.lineno"].AsInt64() == 0 {
+		t.Fatalf("attributes = %v, want service and code attributes", attrs)
+	}
+	// The SDK turns the record error into exception attributes.
+	if attrs["exception.message"].AsString() != errDeclined.Error() {
+		t.Fatalf("exception.message = %v, want the error attribute set as the record error", attrs["exception.message"])
+	}
+	req := make(map[string]attribute.Value)
+	for _, kv := range attrs["req"].AsMap() {
+		req[string(kv.Key)] = kv.Value
+	}
+	if req["id"].AsInt64() != 7 || req["amount"].AsFloat64() != 12.5 || !req["ok"].AsBool() ||
+		req["took"].AsInt64() != int64(1500*time.Millisecond) || string(req["payload"].AsByteSlice()) != "raw" {
+		t.Fatalf("req = %v, want typed values", req)
+	}
+	if tags := req["tags"].AsSlice(); len(tags) != 2 || tags[1].AsString() != "b" {
+		t.Fatalf("req.tags = %v, want a slice", req["tags"])
+	}
+}
+
+func TestLogsHandlerFlushesAndShutsDownTheProvider(t *testing.T) {
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter,
+		sdklog.WithExportInterval(time.Hour))))
+	handler := NewLogsHandler("github.com/acme/billing", WithLoggerProvider(provider))
+	ctx := context.Background()
+
+	handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "charged", 0))
+	if err := logger.FlushHandler(ctx, handler); err != nil || len(exporter.records) != 1 {
+		t.Fatalf("Flush = %v with %d records exported, want 1", err, len(exporter.records))
+	}
+
+	handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "refunded", 0))
+	if err := logger.CloseHandler(ctx, handler); err != nil || len(exporter.records) != 2 {
+		t.Fatalf("Close = %v with %d records exported, want 2", err, len(exporter.records))
+	}
+	handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0))
+	provider.ForceFlush(ctx)
+	if len(exporter.records) != 2 {
+		t.Fatalf("exported %d records after Close, want 2", len(exporter.records))
+	}
+}
+
+func TestSeverityMapsCustomLevels(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  log.Severity
+		text  string
+	}{
+		{logger.LevelTrace, log.SeverityTrace1, "TRACE"},
+		{slog.LevelDebug, log.SeverityDebug1, "DEBUG"},
+		{slog.LevelInfo, log.SeverityInfo1, "INFO"},
+		{slog.LevelWarn, log.SeverityWarn1, "WARN"},
+		{slog.LevelError, log.SeverityError1, "ERROR"},
+		{logger.LevelFatal, log.SeverityFatal1, "FATAL"},
+		{logger.LevelPanic, log.SeverityFatal2, "PANIC"},
+		{slog.Level(-20), log.SeverityTrace1, "DEBUG-16"},
+	}
+	for _, tt := range tests {
+		if got := Severity(tt.level); got != tt.want {
+			t.Errorf("Severity(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+		if got := SeverityText(tt.level); got != tt.text {
+			t.Errorf("SeverityText(%v) = %q, want %q", tt.level, got, tt.text)
+		}
+	}
+}
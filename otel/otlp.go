@@ -0,0 +1,632 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jgolang/log/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultOTLPEndpoint is the logs URL of a local OTLP/HTTP collector.
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/logs"
+
+// maxRetryDelay bounds the wait between export attempts, including the
+// Retry-After asked by the collector.
+const maxRetryDelay = 30 * time.Second
+
+// OTLPHandler is an implementation of slog's Handler interface that sends
+// records to an OpenTelemetry collector as OTLP/HTTP JSON
+// ExportLogsServiceRequest payloads, without the OTel SDK. Records are
+// converted like in LogsHandler, queued, and exported in batches from a
+// background goroutine. The queue is bounded: records handled while it is
+// full are dropped and counted. Failed exports are retried with an
+// exponential backoff, waiting for the Retry-After of 429 and 503 responses,
+// up to maxRetryDelay. Call Close to export the queued records and stop the
+// goroutine.
+type OTLPHandler struct {
+	state  *otlpState
+	attrs  []slog.Attr
+	groups []string
+}
+
+type otlpConfig struct {
+	endpoint     string
+	headers      map[string]string
+	resource     []attribute.KeyValue
+	scopeName    string
+	scopeVersion string
+	gzip         bool
+	batchSize    int
+	interval     time.Duration
+	queueSize    int
+	maxRetries   int
+	client       *http.Client
+	onError      func(error)
+	level        slog.Leveler
+}
+
+// OTLPOpt configures an OTLPHandler.
+type OTLPOpt func(config *otlpConfig)
+
+// WithOTLPHeaders returns an OTLPOpt, which adds headers, such as authentication
+// tokens, to every export request.
+func WithOTLPHeaders(headers map[string]string) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.headers = headers
+	}
+}
+
+// WithOTLPResource returns an OTLPOpt, which sets the resource attributes, such
+// as service.name, sent with every batch.
+func WithOTLPResource(attrs ...attribute.KeyValue) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.resource = attrs
+	}
+}
+
+// WithOTLPScope returns an OTLPOpt, which sets the instrumentation scope.
+func WithOTLPScope(name, version string) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.scopeName = name
+		config.scopeVersion = version
+	}
+}
+
+// WithOTLPGzip returns an OTLPOpt, which compresses export requests with gzip.
+func WithOTLPGzip(enabled bool) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.gzip = enabled
+	}
+}
+
+// WithOTLPBatchSize returns an OTLPOpt, which sets the maximum number of records
+// per export request. Defaults to 512.
+func WithOTLPBatchSize(size int) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.batchSize = size
+	}
+}
+
+// WithOTLPExportInterval returns an OTLPOpt, which sets how long records wait
+// for a full batch before being exported. Defaults to 1s.
+func WithOTLPExportInterval(interval time.Duration) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.interval = interval
+	}
+}
+
+// WithOTLPQueueSize returns an OTLPOpt, which bounds the number of records
+// waiting for export. Defaults to 2048.
+func WithOTLPQueueSize(size int) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.queueSize = size
+	}
+}
+
+// WithOTLPMaxRetries returns an OTLPOpt, which sets how many times a failed
+// export is retried before its records are dropped. Defaults to 5.
+func WithOTLPMaxRetries(retries int) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.maxRetries = retries
+	}
+}
+
+// WithOTLPHTTPClient returns an OTLPOpt, which sets the client used for export
+// requests. Defaults to a client with a 10s timeout.
+func WithOTLPHTTPClient(client *http.Client) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.client = client
+	}
+}
+
+// WithOTLPErrorHandler returns an OTLPOpt, which receives the errors of exports
+// that failed after all retries.
+func WithOTLPErrorHandler(onError func(error)) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.onError = onError
+	}
+}
+
+// WithOTLPLevel returns an OTLPOpt, which sets the minimum level handled.
+// Defaults to INFO.
+func WithOTLPLevel(level slog.Leveler) OTLPOpt {
+	return func(config *otlpConfig) {
+		config.level = level
+	}
+}
+
+type otlpItem struct {
+	record otlpLogRecord
+	flush  chan struct{} // Set for flush markers instead of a record.
+}
+
+// otlpState is shared by an OTLPHandler and the handlers derived from it.
+type otlpState struct {
+	config   otlpConfig
+	queue    chan otlpItem
+	mu       sync.RWMutex // Guards closed against concurrent sends.
+	closed   bool
+	done     chan struct{}
+	stop     chan struct{} // Closed when Close gives up waiting for the exports.
+	stopOnce sync.Once
+	dropped  atomic.Uint64
+	after    func(time.Duration) <-chan time.Time
+}
+
+// NewOTLPHandler creates an OTLPHandler exporting to endpoint, or to
+// DefaultOTLPEndpoint when empty.
+func NewOTLPHandler(endpoint string, opts ...OTLPOpt) *OTLPHandler {
+	config := otlpConfig{
+		endpoint:   endpoint,
+		batchSize:  512,
+		interval:   time.Second,
+		queueSize:  2048,
+		maxRetries: 5,
+		level:      slog.LevelInfo,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.endpoint == "" {
+		config.endpoint = DefaultOTLPEndpoint
+	}
+	if config.client == nil {
+		config.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	config.batchSize = max(config.batchSize, 1)
+	config.queueSize = max(config.queueSize, 1)
+	if config.interval <= 0 {
+		config.interval = time.Second
+	}
+	state := &otlpState{
+		config: config,
+		queue:  make(chan otlpItem, config.queueSize),
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		after:  time.After,
+	}
+	go state.run()
+	return &OTLPHandler{state: state}
+}
+
+// Enabled reports whether level reaches the handler level.
+func (h *OTLPHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.state.config.level.Level()
+}
+
+// Handle converts the record and queues it, or drops it if the queue is full.
+func (h *OTLPHandler) Handle(ctx context.Context, record slog.Record) error {
+	kvs, err := convertRecord(record, h.attrs, h.groups)
+	if err != nil {
+		kvs = append(kvs,
+			attribute.String("exception.type", fmt.Sprintf("%T", err)),
+			attribute.String("exception.message", err.Error()),
+		)
+	}
+	timestamp := "0" // Unknown time.
+	if !record.Time.IsZero() {
+		timestamp = strconv.FormatInt(record.Time.UnixNano(), 10)
+	}
+	out := otlpLogRecord{
+		TimeUnixNano:         timestamp,
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       int(Severity(record.Level)),
+		SeverityText:         SeverityText(record.Level),
+		Body:                 otlpAnyValue(attribute.StringValue(record.Message)),
+		Attributes:           otlpAttributes(kvs),
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			out.TraceID = sc.TraceID().String()
+			out.SpanID = sc.SpanID().String()
+			out.Flags = uint32(sc.TraceFlags())
+		}
+	}
+
+	s := h.state
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return logger.ErrHandlerClosed
+	}
+	select {
+	case s.queue <- otlpItem{record: out}:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs returns an OTLPHandler sharing the queue of h.
+func (h *OTLPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = logger.InsertAttrs(h.attrs, h.groups, attrs)
+	return &clone
+}
+
+// WithGroup returns an OTLPHandler sharing the queue of h.
+func (h *OTLPHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// Dropped returns the number of records dropped because the queue was full,
+// or because they could not be encoded or exported.
+func (h *OTLPHandler) Dropped() uint64 {
+	return h.state.dropped.Load()
+}
+
+// Flush exports the records queued before the call, or returns when ctx is done.
+func (h *OTLPHandler) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	s := h.state
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return logger.ErrHandlerClosed
+	}
+	select {
+	case s.queue <- otlpItem{flush: done}:
+		s.mu.RUnlock()
+	case <-ctx.Done():
+		s.mu.RUnlock()
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting records and exports the queued ones, or returns when
+// ctx is done. Then the pending retries are abandoned and the records still
+// queued are dropped. Records handled after Close return
+// logger.ErrHandlerClosed.
+func (h *OTLPHandler) Close(ctx context.Context) error {
+	s := h.state
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.stopOnce.Do(func() { close(s.stop) })
+		return ctx.Err()
+	}
+}
+
+func (s *otlpState) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.interval)
+	defer ticker.Stop()
+	batch := make([]otlpLogRecord, 0, s.config.batchSize)
+	export := func() {
+		if len(batch) > 0 {
+			s.export(batch)
+			batch = make([]otlpLogRecord, 0, s.config.batchSize)
+		}
+	}
+	for {
+		select {
+		case item, ok := <-s.queue:
+			if !ok {
+				export()
+				return
+			}
+			if item.flush != nil {
+				export()
+				close(item.flush)
+				continue
+			}
+			batch = append(batch, item.record)
+			if len(batch) >= s.config.batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		}
+	}
+}
+
+// export sends batch, retrying failed requests. The records of a batch that
+// cannot be sent are counted as dropped.
+func (s *otlpState) export(batch []otlpLogRecord) {
+	err := s.send(batch)
+	if err == nil {
+		return
+	}
+	s.dropped.Add(uint64(len(batch)))
+	if s.config.onError != nil {
+		s.config.onError(fmt.Errorf("otel: export %d log records: %w", len(batch), err))
+	}
+}
+
+func (s *otlpState) send(batch []otlpLogRecord) error {
+	if s.stopped() {
+		return errExportStopped
+	}
+	body, err := s.encode(batch)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		retry, retryAfter, err := s.post(body)
+		if err == nil || !retry || attempt >= s.config.maxRetries {
+			return err
+		}
+		if retryAfter <= 0 {
+			retryAfter = 500 * time.Millisecond << attempt
+		}
+		select {
+		case <-s.after(min(retryAfter, maxRetryDelay)):
+		case <-s.stop:
+			return errors.Join(err, errExportStopped)
+		}
+	}
+}
+
+var errExportStopped = errors.New("otel: exporter closed before the records were sent")
+
+func (s *otlpState) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *otlpState) encode(batch []otlpLogRecord) ([]byte, error) {
+	request := otlpExportRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: otlpAttributes(s.config.resource)},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: s.config.scopeName, Version: s.config.scopeVersion},
+			LogRecords: batch,
+		}},
+	}}}
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var zw *gzip.Writer
+	if s.config.gzip {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+	if err := json.NewEncoder(w).Encode(request); err != nil {
+		return nil, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends one request and reports whether a failure can be retried and
+// how long the collector asked to wait.
+func (s *otlpState) post(body []byte) (retry bool, retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.config.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for key, value := range s.config.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.config.client.Do(req)
+	if err != nil {
+		return true, 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, 0, nil
+	}
+	err = errors.New("otel: collector responded " + resp.Status)
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true, parseRetryAfter(resp.Header.Get("Retry-After")), err
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true, 0, err
+	}
+	return false, 0, err
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as a date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// The types below follow the OTLP/JSON encoding of ExportLogsServiceRequest:
+// lowerCamelCase names, 64-bit integers as strings and IDs in hex.
+
+type otlpExportRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpValue      `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+	Flags                uint32         `json:"flags,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	BoolValue   *bool       `json:"boolValue,omitempty"`
+	IntValue    *string     `json:"intValue,omitempty"`
+	DoubleValue *otlpDouble `json:"doubleValue,omitempty"`
+	BytesValue  []byte      `json:"bytesValue,omitempty"`
+	ArrayValue  *otlpValues `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKvlist `json:"kvlistValue,omitempty"`
+}
+
+// otlpDouble encodes the non-finite values JSON numbers cannot hold as the
+// strings "NaN", "Infinity" and "-Infinity", like the OTLP/JSON encoding.
+type otlpDouble float64
+
+func (d otlpDouble) MarshalJSON() ([]byte, error) {
+	switch f := float64(d); {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	default:
+		return json.Marshal(f)
+	}
+}
+
+func (d *otlpDouble) UnmarshalJSON(data []byte) error {
+	var f float64
+	switch string(data) {
+	case `"NaN"`:
+		f = math.NaN()
+	case `"Infinity"`:
+		f = math.Inf(1)
+	case `"-Infinity"`:
+		f = math.Inf(-1)
+	default:
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+	}
+	*d = otlpDouble(f)
+	return nil
+}
+
+type otlpValues struct {
+	Values []otlpValue `json:"values"`
+}
+
+type otlpKvlist struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+func otlpAttributes(kvs []attribute.KeyValue) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		out = append(out, otlpKeyValue{Key: string(kv.Key), Value: otlpAnyValue(kv.Value)})
+	}
+	return out
+}
+
+func otlpAnyValue(v attribute.Value) otlpValue {
+	switch v.Type() {
+	case attribute.STRING:
+		s := v.AsString()
+		return otlpValue{StringValue: &s}
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := otlpDouble(v.AsFloat64())
+		return otlpValue{DoubleValue: &f}
+	case attribute.BYTESLICE:
+		return otlpValue{BytesValue: v.AsByteSlice()}
+	case attribute.MAP:
+		return otlpValue{KvlistValue: &otlpKvlist{Values: otlpAttributes(v.AsMap())}}
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE, attribute.SLICE:
+		values := []otlpValue{}
+		for _, item := range sliceValues(v) {
+			values = append(values, otlpAnyValue(item))
+		}
+		return otlpValue{ArrayValue: &otlpValues{Values: values}}
+	}
+	return otlpValue{}
+}
+
+// sliceValues returns the items of a slice value of any type.
+func sliceValues(v attribute.Value) []attribute.Value {
+	var values []attribute.Value
+	switch v.Type() {
+	case attribute.BOOLSLICE:
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, attribute.BoolValue(b))
+		}
+	case attribute.INT64SLICE:
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, attribute.Int64Value(i))
+		}
+	case attribute.FLOAT64SLICE:
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, attribute.Float64Value(f))
+		}
+	case attribute.STRINGSLICE:
+		for _, s := range v.AsStringSlice() {
+			values = append(values, attribute.StringValue(s))
+		}
+	case attribute.SLICE:
+		values = v.AsSlice()
+	}
+	return values
+}
@@ -0,0 +1,378 @@
+package otel
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jgolang/log/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// collector is an httptest.Server accepting OTLP/HTTP JSON log requests.
+type collector struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []otlpExportRequest
+	headers  []http.Header
+	respond  func(w http.ResponseWriter, attempt int) bool // Reports whether the request was accepted.
+}
+
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{}
+	attempts := 0
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		attempt := attempts
+		attempts++
+		respond := c.respond
+		c.mu.Unlock()
+		if respond != nil && !respond(w, attempt) {
+			return
+		}
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip: %v", err)
+				return
+			}
+			body = zr
+		}
+		var request otlpExportRequest
+		if err := json.NewDecoder(body).Decode(&request); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		c.mu.Lock()
+		c.requests = append(c.requests, request)
+		c.headers = append(c.headers, r.Header.Clone())
+		c.mu.Unlock()
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *collector) records() []otlpLogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var records []otlpLogRecord
+	for _, request := range c.requests {
+		for _, resourceLogs := range request.ResourceLogs {
+			for _, scopeLogs := range resourceLogs.ScopeLogs {
+				records = append(records, scopeLogs.LogRecords...)
+			}
+		}
+	}
+	return records
+}
+
+func otlpAttr(attrs []otlpKeyValue, key string) (otlpValue, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return otlpValue{}, false
+}
+
+func slogRecord(msg string, attrs ...slog.Attr) slog.Record {
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+	record.AddAttrs(attrs...)
+	return record
+}
+
+func TestOTLPHandlerExportsBatches(t *testing.T) {
+	c := newCollector(t)
+	handler := NewOTLPHandler(c.URL+"/v1/logs",
+		WithOTLPResource(attribute.String("service.name", "billing")),
+		WithOTLPScope("github.com/acme/billing", "1.2.0"),
+		WithOTLPHeaders(map[string]string{"Authorization": "Bearer token"}),
+		WithOTLPGzip(true),
+		WithOTLPBatchSize(2),
+		WithOTLPExportInterval(time.Hour),
+	)
+	l := logger.NewWithOptions(logger.WithHandler(handler), logger.WithSource(false))
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	l.InfoContext(ctx, "charged", "amount", 42, "tags", []string{"a", "b"})
+	l.Warn("slow", "elapsed", 1.5)
+	l.Error("failed", "err", errDeclined)
+	if err := handler.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	c.mu.Lock()
+	if len(c.requests) != 2 {
+		t.Fatalf("requests = %d, want 2 batches", len(c.requests))
+	}
+	resourceLogs := c.requests[0].ResourceLogs[0]
+	header := c.headers[0]
+	c.mu.Unlock()
+	if header.Get("Authorization") != "Bearer token" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("headers = %v", header)
+	}
+	if v, _ := otlpAttr(resourceLogs.Resource.Attributes, "service.name"); v.StringValue == nil || *v.StringValue != "billing" {
+		t.Errorf("resource = %+v", resourceLogs.Resource)
+	}
+	if scope := resourceLogs.ScopeLogs[0].Scope; scope.Name != "github.com/acme/billing" || scope.Version != "1.2.0" {
+		t.Errorf("scope = %+v", scope)
+	}
+
+	records := c.records()
+	if len(records) != 3 {
+		t.Fatalf("records = %d, want 3", len(records))
+	}
+	charged := records[0]
+	if *charged.Body.StringValue != "charged" || charged.SeverityNumber != 9 || charged.SeverityText != "INFO" {
+		t.Errorf("record = %+v", charged)
+	}
+	if charged.TraceID != traceID.String() || charged.SpanID != spanID.String() || charged.Flags != 1 {
+		t.Errorf("trace = %q %q %d", charged.TraceID, charged.SpanID, charged.Flags)
+	}
+	if v, _ := otlpAttr(charged.Attributes, "amount"); v.IntValue == nil || *v.IntValue != "42" {
+		t.Errorf("amount = %+v", v)
+	}
+	if v, _ := otlpAttr(charged.Attributes, "tags"); v.ArrayValue == nil || len(v.ArrayValue.Values) != 2 {
+		t.Errorf("tags = %+v", v)
+	}
+	if v, _ := otlpAttr(records[1].Attributes, "elapsed"); v.DoubleValue == nil || *v.DoubleValue != 1.5 {
+		t.Errorf("elapsed = %+v", v)
+	}
+	if v, _ := otlpAttr(records[2].Attributes, "exception.message"); v.StringValue == nil || *v.StringValue != "card declined" {
+		t.Errorf("exception.message = %+v", v)
+	}
+	if err := handler.Handle(context.Background(), slogRecord("late")); err != logger.ErrHandlerClosed {
+		t.Errorf("Handle after Close = %v, want ErrHandlerClosed", err)
+	}
+}
+
+func TestOTLPHandlerRetriesHonoringRetryAfter(t *testing.T) {
+	c := newCollector(t)
+	c.respond = func(w http.ResponseWriter, attempt int) bool {
+		switch attempt {
+		case 0:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return false
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+			return false
+		}
+		return true
+	}
+	handler := NewOTLPHandler(c.URL, WithOTLPExportInterval(time.Hour))
+	var slept []time.Duration
+	handler.state.after = func(d time.Duration) <-chan time.Time {
+		slept = append(slept, d)
+		return elapsed()
+	}
+
+	handler.Handle(context.Background(), slogRecord("charged"))
+	if err := handler.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(slept) != 2 || slept[0] != 7*time.Second || slept[1] != time.Second {
+		t.Errorf("slept = %v, want [7s 1s]", slept)
+	}
+	if records := c.records(); len(records) != 1 {
+		t.Errorf("records = %d, want 1", len(records))
+	}
+	handler.Close(context.Background())
+}
+
+func TestOTLPHandlerReportsPermanentFailures(t *testing.T) {
+	c := newCollector(t)
+	c.respond = func(w http.ResponseWriter, _ int) bool {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	var errs []error
+	handler := NewOTLPHandler(c.URL, WithOTLPErrorHandler(func(err error) { errs = append(errs, err) }))
+	handler.state.after = func(time.Duration) <-chan time.Time {
+		t.Error("retried a 400 response")
+		return elapsed()
+	}
+
+	handler.Handle(context.Background(), slogRecord("charged"))
+	handler.Close(context.Background())
+	if len(errs) != 1 || handler.Dropped() != 1 {
+		t.Fatalf("errors = %v, dropped = %d, want one of each", errs, handler.Dropped())
+	}
+}
+
+// elapsed returns a channel ready to receive, for waits that end at once.
+func elapsed() <-chan time.Time {
+	c := make(chan time.Time, 1)
+	c <- time.Time{}
+	return c
+}
+
+func TestOTLPHandlerCapsRetryAfter(t *testing.T) {
+	c := newCollector(t)
+	c.respond = func(w http.ResponseWriter, attempt int) bool {
+		if attempt == 0 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return false
+		}
+		return true
+	}
+	handler := NewOTLPHandler(c.URL, WithOTLPExportInterval(time.Hour))
+	var slept []time.Duration
+	handler.state.after = func(d time.Duration) <-chan time.Time {
+		slept = append(slept, d)
+		return elapsed()
+	}
+
+	handler.Handle(context.Background(), slogRecord("charged"))
+	handler.Close(context.Background())
+	if len(slept) != 1 || slept[0] != maxRetryDelay {
+		t.Errorf("slept = %v, want [%v]", slept, maxRetryDelay)
+	}
+}
+
+func TestOTLPHandlerCloseInterruptsRetryWait(t *testing.T) {
+	c := newCollector(t)
+	c.respond = func(w http.ResponseWriter, _ int) bool {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+	handler := NewOTLPHandler(c.URL, WithOTLPExportInterval(time.Hour))
+	waiting := make(chan struct{})
+	handler.state.after = func(time.Duration) <-chan time.Time {
+		close(waiting)
+		return nil // Never fires.
+	}
+
+	handler.Handle(context.Background(), slogRecord("charged"))
+	go handler.Flush(context.Background())
+	<-waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := handler.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close() error = %v, want deadline exceeded", err)
+	}
+	select {
+	case <-handler.state.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the export goroutine to stop")
+	}
+	if dropped := handler.Dropped(); dropped != 1 {
+		t.Errorf("Dropped() = %d, want 1", dropped)
+	}
+}
+
+func TestOTLPHandlerClosesConcurrentlyAfterTimeout(t *testing.T) {
+	c := newCollector(t)
+	c.respond = func(w http.ResponseWriter, _ int) bool {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+	handler := NewOTLPHandler(c.URL, WithOTLPExportInterval(time.Hour))
+	waiting := make(chan struct{})
+	var once sync.Once
+	handler.state.after = func(time.Duration) <-chan time.Time {
+		once.Do(func() { close(waiting) })
+		return nil // Never fires.
+	}
+
+	handler.Handle(context.Background(), slogRecord("charged"))
+	go handler.Flush(context.Background())
+	<-waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.Close(ctx)
+		}()
+	}
+	wg.Wait()
+	<-handler.state.done
+}
+
+func TestOTLPHandlerEncodesNonFiniteDoublesAndZeroTime(t *testing.T) {
+	c := newCollector(t)
+	handler := NewOTLPHandler(c.URL, WithOTLPExportInterval(time.Hour))
+
+	handler.Handle(context.Background(), slogRecord("nan", slog.Float64("ratio", math.NaN())))
+	handler.Handle(context.Background(), slogRecord("inf", slog.Float64("ratio", math.Inf(-1))))
+	handler.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "untimed", 0))
+	handler.Close(context.Background())
+
+	records := c.records()
+	if len(records) != 3 || handler.Dropped() != 0 {
+		t.Fatalf("records = %d, dropped = %d, want 3 and 0", len(records), handler.Dropped())
+	}
+	if v, _ := otlpAttr(records[0].Attributes, "ratio"); v.DoubleValue == nil || !math.IsNaN(float64(*v.DoubleValue)) {
+		t.Errorf("NaN ratio = %+v", v)
+	}
+	if v, _ := otlpAttr(records[1].Attributes, "ratio"); v.DoubleValue == nil || !math.IsInf(float64(*v.DoubleValue), -1) {
+		t.Errorf("-Inf ratio = %+v", v)
+	}
+	if records[2].TimeUnixNano != "0" {
+		t.Errorf("timeUnixNano = %q, want 0 for a zero time", records[2].TimeUnixNano)
+	}
+}
+
+func TestOTLPHandlerDropsRecordsWhenQueueIsFull(t *testing.T) {
+	c := newCollector(t)
+	release := make(chan struct{})
+	c.respond = func(w http.ResponseWriter, _ int) bool {
+		<-release
+		return true
+	}
+	handler := NewOTLPHandler(c.URL, WithOTLPBatchSize(1), WithOTLPQueueSize(2), WithOTLPExportInterval(time.Hour))
+
+	// The first record is exported and blocks the goroutine, two fill the
+	// queue and the rest are dropped.
+	handler.Handle(context.Background(), slogRecord("first"))
+	deadline := time.Now().Add(5 * time.Second)
+	for len(handler.state.queue) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	for range 5 {
+		handler.Handle(context.Background(), slogRecord("queued"))
+	}
+	if dropped := handler.Dropped(); dropped != 3 {
+		t.Errorf("Dropped() = %d, want 3", dropped)
+	}
+	close(release)
+	handler.Close(context.Background())
+	if records := c.records(); len(records) != 3 {
+		t.Errorf("records = %d, want 3", len(records))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(date); d <= 58*time.Second || d > time.Minute {
+		t.Errorf("parseRetryAfter(date) = %v", d)
+	}
+	if d := parseRetryAfter("3"); d != 3*time.Second {
+		t.Errorf("parseRetryAfter(3) = %v", d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Errorf("parseRetryAfter(soon) = %v", d)
+	}
+}